@@ -0,0 +1,81 @@
+// Command audit verifies and exports the validator signature audit log
+//
+//	go run ./cmd/audit verify [-file signature_audit.jsonl]
+//	go run ./cmd/audit export [-file signature_audit.jsonl] [-since 2025-01-01T00:00:00Z] [-until ...] > signatures.jsonl
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"sample-game-backend/internal/audit"
+	"sample-game-backend/internal/config"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "verify":
+		flags := flag.NewFlagSet("verify", flag.ExitOnError)
+		path := flags.String("file", config.InitConfig().Audit.Path, "audit log file")
+		flags.Parse(os.Args[2:])
+
+		file := openLog(*path)
+		defer file.Close()
+		count, err := audit.Verify(file)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Fprintf(os.Stderr, "audit log OK: %d entries\n", count)
+	case "export":
+		flags := flag.NewFlagSet("export", flag.ExitOnError)
+		path := flags.String("file", config.InitConfig().Audit.Path, "audit log file")
+		since := flags.String("since", "", "only entries at or after this RFC3339 time")
+		until := flags.String("until", "", "only entries before this RFC3339 time")
+		flags.Parse(os.Args[2:])
+
+		file := openLog(*path)
+		defer file.Close()
+		count, err := audit.Export(file, os.Stdout, parseTime("since", *since), parseTime("until", *until))
+		if err != nil {
+			fail(err)
+		}
+		fmt.Fprintf(os.Stderr, "exported %d entries\n", count)
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: audit verify|export [-file path] [-since time] [-until time]")
+	os.Exit(2)
+}
+
+func openLog(path string) *os.File {
+	file, err := os.Open(path)
+	if err != nil {
+		fail(err)
+	}
+	return file
+}
+
+func parseTime(name, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		fail(fmt.Errorf("invalid -%s: %w", name, err))
+	}
+	return parsed
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
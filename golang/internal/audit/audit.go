@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"sample-game-backend/internal/models"
+)
+
+// GenesisHash previous hash of the first entry
+var GenesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// maxLineSize longest entry accepted when reading a log
+const maxLineSize = 1 << 20
+
+// Record signature details supplied by the validate path
+type Record struct {
+	Digest       string                 `json:"digest"`
+	ValidatorSig string                 `json:"validator_sig"`
+	Signer       string                 `json:"signer"`
+	UserAddress  string                 `json:"user_address"`
+	SessionID    string                 `json:"session_id"`
+	ProjectID    string                 `json:"project_id"`
+	UUID         string                 `json:"uuid"`
+	Intent       *models.ExchangeIntent `json:"intent,omitempty"`
+	// ClientIdentity verified client certificate name of the requester (mutual TLS only)
+	ClientIdentity string `json:"client_identity,omitempty"`
+}
+
+// Entry one line of the audit log, chained to the previous entry by PrevHash
+type Entry struct {
+	Seq       uint64    `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	Record
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// ComputeHash hex SHA-256 of the entry's JSON encoding without its own hash
+func (e Entry) ComputeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ChainError the log is broken at an entry (edited, removed, reordered or unreadable lines)
+type ChainError struct {
+	Line   int
+	Seq    uint64
+	Reason string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("audit chain broken at line %d (seq %d): %s", e.Line, e.Seq, e.Reason)
+}
+
+// Log append-only, hash-chained signature audit log stored as JSON lines
+type Log struct {
+	mu       sync.Mutex
+	file     *os.File
+	path     string
+	seq      uint64
+	lastHash string
+	now      func() time.Time
+}
+
+// Open open the log at path, creating it when missing
+// The existing chain is verified so new entries are never appended to a tampered log.
+func Open(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+
+	l := &Log{file: file, path: path, lastHash: GenesisHash, now: time.Now}
+	err = Walk(file, func(entry *Entry) error {
+		l.seq = entry.Seq
+		l.lastHash = entry.Hash
+		return nil
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// WithClock replace the timestamp source (for tests)
+func (l *Log) WithClock(now func() time.Time) *Log {
+	l.now = now
+	return l
+}
+
+// Append chain the record to the log and sync it to disk
+func (l *Log) Append(record Record) (*Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := &Entry{
+		Seq:       l.seq + 1,
+		Timestamp: l.now().UTC(),
+		Record:    record,
+		PrevHash:  l.lastHash,
+	}
+	hash, err := entry.ComputeHash()
+	if err != nil {
+		return nil, fmt.Errorf("hash audit entry: %w", err)
+	}
+	entry.Hash = hash
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("encode audit entry: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("write audit entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return nil, fmt.Errorf("sync audit log: %w", err)
+	}
+
+	l.seq = entry.Seq
+	l.lastHash = entry.Hash
+	return entry, nil
+}
+
+// Check check the open log file is still the file at its path
+// Fails when the file was removed, rotated or replaced underneath the process.
+func (l *Log) Check() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	open, err := l.file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat audit log: %w", err)
+	}
+	current, err := os.Stat(l.path)
+	if err != nil {
+		return fmt.Errorf("stat audit log: %w", err)
+	}
+	if !os.SameFile(open, current) {
+		return fmt.Errorf("audit log %s was replaced", l.path)
+	}
+	return nil
+}
+
+// Close close the log file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Walk read the log from r, verifying the chain, and call fn for every entry in order
+// Returns *ChainError at the first entry that does not continue the chain.
+func Walk(r io.Reader, fn func(*Entry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	prevHash := GenesisHash
+	var seq uint64
+	line := 0
+	for scanner.Scan() {
+		line++
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return &ChainError{Line: line, Seq: seq + 1, Reason: "invalid JSON: " + err.Error()}
+		}
+		if entry.Seq != seq+1 {
+			return &ChainError{Line: line, Seq: entry.Seq, Reason: fmt.Sprintf("expected seq %d", seq+1)}
+		}
+		if entry.PrevHash != prevHash {
+			return &ChainError{Line: line, Seq: entry.Seq, Reason: "prev_hash does not match the previous entry"}
+		}
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			return &ChainError{Line: line, Seq: entry.Seq, Reason: err.Error()}
+		}
+		if entry.Hash != hash {
+			return &ChainError{Line: line, Seq: entry.Seq, Reason: "hash does not match the entry contents"}
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+		prevHash = entry.Hash
+		seq = entry.Seq
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return &ChainError{Line: line + 1, Seq: seq + 1, Reason: "entry too long"}
+		}
+		return fmt.Errorf("read audit log: %w", err)
+	}
+	return nil
+}
+
+// Verify verify the whole chain read from r and return the number of entries
+func Verify(r io.Reader) (int, error) {
+	count := 0
+	err := Walk(r, func(*Entry) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// Export write verified entries created in [since, until) to w as JSON lines (zero bounds are open)
+// The whole chain is verified; entries already written stay in w when a later entry is broken.
+func Export(r io.Reader, w io.Writer, since, until time.Time) (int, error) {
+	encoder := json.NewEncoder(w)
+	count := 0
+	err := Walk(r, func(entry *Entry) error {
+		if (!since.IsZero() && entry.Timestamp.Before(since)) || (!until.IsZero() && !entry.Timestamp.Before(until)) {
+			return nil
+		}
+		count++
+		return encoder.Encode(entry)
+	})
+	return count, err
+}
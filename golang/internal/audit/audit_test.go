@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clockAt 호출할 때마다 1분씩 증가하는 테스트 시계
+func clockAt(start time.Time) func() time.Time {
+	now := start
+	return func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+}
+
+func record(uuid string) Record {
+	return Record{
+		Digest:       "0x5c8a4f1e9d2b7c3a6e0f8d1b4a7c2e5f9d3b6a0c8e1f4d7b2a5c9e3f6d0b8a1c",
+		ValidatorSig: "0xabcdef",
+		Signer:       "0x100cbc7ac2abdb4e75d8e08c6842d1dd8c04df73",
+		UserAddress:  "0x2222222222222222222222222222222222222222",
+		SessionID:    "audit-session",
+		ProjectID:    "audit-project",
+		UUID:         uuid,
+		Intent: &models.ExchangeIntent{
+			Type:   models.IntentTypeAssemble,
+			Method: "mint",
+			From:   []models.PairAsset{{Type: "asset", AssetID: "gold", Amount: 100}},
+			To:     []models.PairAsset{{Type: "erc20", AssetID: "0x3333333333333333333333333333333333333333", Amount: 1}},
+		},
+	}
+}
+
+// writeLog 세 개의 항목을 기록한 로그 파일 경로
+func writeLog(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(path)
+	require.NoError(t, err)
+	log.WithClock(clockAt(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	for _, uuid := range []string{"order-1", "order-2", "order-3"} {
+		_, err := log.Append(record(uuid))
+		require.NoError(t, err)
+	}
+	require.NoError(t, log.Close())
+	return path
+}
+
+func readLines(t *testing.T, path string) []string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestAppendAndReopen(t *testing.T) {
+	path := writeLog(t)
+
+	// 다시 열면 체인을 이어서 기록
+	log, err := Open(path)
+	require.NoError(t, err)
+	entry, err := log.Append(record("order-4"))
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+	assert.Equal(t, uint64(4), entry.Seq)
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	count, err := Verify(file)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	lines := readLines(t, path)
+	var first Entry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, GenesisHash, first.PrevHash)
+	assert.Equal(t, "order-1", first.UUID)
+	assert.Equal(t, "gold", first.Intent.From[0].AssetID)
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	lines := readLines(t, writeLog(t))
+
+	tests := []struct {
+		name   string
+		lines  []string
+		line   int
+		reason string
+	}{
+		{"edited field", []string{lines[0], strings.Replace(lines[1], "audit-session", "other-session", 1), lines[2]}, 2, "hash does not match"},
+		{"removed entry", []string{lines[0], lines[2]}, 2, "expected seq 2"},
+		{"reordered entries", []string{lines[1], lines[0], lines[2]}, 1, "expected seq 1"},
+		{"truncated line", []string{lines[0], lines[1][:20]}, 2, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(strings.Join(tt.lines, "\n")))
+			var chainErr *ChainError
+			require.True(t, errors.As(err, &chainErr), "%v", err)
+			assert.Equal(t, tt.line, chainErr.Line)
+			assert.Contains(t, chainErr.Reason, tt.reason)
+		})
+	}
+
+	// 변조된 로그에는 더 이상 기록하지 않음
+	path := filepath.Join(t.TempDir(), "tampered.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(lines[0]+"\n"+lines[2]+"\n"), 0o600))
+	_, err := Open(path)
+	assert.Error(t, err)
+}
+
+func TestExport(t *testing.T) {
+	path := writeLog(t)
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	// 항목 시각은 00:01, 00:02, 00:03
+	var out bytes.Buffer
+	count, err := Export(file, &out, time.Date(2025, 1, 1, 0, 2, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 3, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	var entry Entry
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "order-2", entry.UUID)
+	assert.Equal(t, uint64(2), entry.Seq)
+}
+
+func TestCheckDetectsReplacedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(path)
+	require.NoError(t, err)
+	defer log.Close()
+	require.NoError(t, log.Check())
+
+	// 파일이 삭제되면 기록할 수 없는 상태
+	require.NoError(t, os.Remove(path))
+	assert.Error(t, log.Check())
+
+	// 같은 경로에 새 파일이 생겨도 열린 파일과 다르면 실패
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	assert.Error(t, log.Check())
+}
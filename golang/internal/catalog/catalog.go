@@ -0,0 +1,235 @@
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"sample-game-backend/internal/models"
+)
+
+// Supported display languages
+const (
+	LanguageKorean             = "ko"
+	LanguageEnglish            = "en"
+	LanguageChineseSimplified  = "zh"
+	LanguageChineseTraditional = "zh-Hant"
+)
+
+// Asset scopes
+const (
+	// ScopeCharacter asset held in each character's inventory
+	ScopeCharacter = "character"
+	// ScopeAccount asset shared by all characters of an account (v2 "common")
+	ScopeAccount = "account"
+)
+
+//go:embed default_catalog.json
+var defaultCatalogJSON []byte
+
+// Catalog global variables
+var (
+	current   *Catalog
+	catalogMu sync.RWMutex
+)
+
+// AssetDefinition asset catalog entry
+type AssetDefinition struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Decimals int               `json:"decimals"`
+	Icon     string            `json:"icon"`
+	Scope    string            `json:"scope,omitempty"`
+	Names    map[string]string `json:"names"`
+}
+
+// Catalog asset catalog structure
+type Catalog struct {
+	DefaultLanguage string            `json:"default_language"`
+	Assets          []AssetDefinition `json:"assets"`
+
+	byID map[string]int
+}
+
+// Parse parse catalog JSON
+func Parse(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid asset catalog: %w", err)
+	}
+
+	if c.DefaultLanguage == "" {
+		c.DefaultLanguage = LanguageEnglish
+	}
+	c.DefaultLanguage = NormalizeLanguage(c.DefaultLanguage)
+
+	c.byID = make(map[string]int, len(c.Assets))
+	for i, asset := range c.Assets {
+		if asset.ID == "" {
+			return nil, fmt.Errorf("invalid asset catalog: asset at index %d has no id", i)
+		}
+		if _, exists := c.byID[asset.ID]; exists {
+			return nil, fmt.Errorf("invalid asset catalog: duplicate asset id %s", asset.ID)
+		}
+
+		switch asset.Scope {
+		case "":
+			c.Assets[i].Scope = ScopeCharacter
+		case ScopeCharacter, ScopeAccount:
+		default:
+			return nil, fmt.Errorf("invalid asset catalog: unknown scope %s for asset %s", asset.Scope, asset.ID)
+		}
+
+		// Normalize language keys so lookups are case-insensitive
+		names := make(map[string]string, len(asset.Names))
+		for lang, name := range asset.Names {
+			names[NormalizeLanguage(lang)] = name
+		}
+		c.Assets[i].Names = names
+		c.byID[asset.ID] = i
+	}
+
+	return &c, nil
+}
+
+// Load load catalog from file
+func Load(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset catalog: %w", err)
+	}
+	return Parse(data)
+}
+
+// InitCatalog load catalog from file and set it as the current catalog
+func InitCatalog(path string) error {
+	c, err := Load(path)
+	if err != nil {
+		slog.Error("InitCatalog", "error", "Failed to load asset catalog", "err", err, "path", path)
+		return err
+	}
+
+	catalogMu.Lock()
+	current = c
+	catalogMu.Unlock()
+
+	slog.Info("InitCatalog", "status", "success", "path", path, "assets", len(c.Assets))
+	return nil
+}
+
+// Get return current catalog (embedded default catalog if none loaded)
+func Get() *Catalog {
+	catalogMu.RLock()
+	c := current
+	catalogMu.RUnlock()
+	if c != nil {
+		return c
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if current == nil {
+		c, err := Parse(defaultCatalogJSON)
+		if err != nil {
+			panic(err)
+		}
+		current = c
+	}
+	return current
+}
+
+// Lookup find asset definition by ID
+func (c *Catalog) Lookup(id string) (AssetDefinition, bool) {
+	i, ok := c.byID[id]
+	if !ok {
+		return AssetDefinition{}, false
+	}
+	return c.Assets[i], true
+}
+
+// IDs return asset IDs in catalog order
+func (c *Catalog) IDs() []string {
+	ids := make([]string, 0, len(c.Assets))
+	for _, asset := range c.Assets {
+		ids = append(ids, asset.ID)
+	}
+	return ids
+}
+
+// IDsByScope return asset IDs of the given scope in catalog order
+func (c *Catalog) IDsByScope(scope string) []string {
+	var ids []string
+	for _, asset := range c.Assets {
+		if asset.Scope == scope {
+			ids = append(ids, asset.ID)
+		}
+	}
+	return ids
+}
+
+// IsAccountScoped report whether asset is held at account level
+func (c *Catalog) IsAccountScoped(id string) bool {
+	asset, ok := c.Lookup(id)
+	return ok && asset.Scope == ScopeAccount
+}
+
+// Metadata return localized metadata for asset (nil if asset is not in catalog)
+func (c *Catalog) Metadata(id, language string) *models.AssetMetadata {
+	asset, ok := c.Lookup(id)
+	if !ok {
+		return nil
+	}
+
+	return &models.AssetMetadata{
+		Name:     c.DisplayName(asset, language),
+		Type:     asset.Type,
+		Decimals: asset.Decimals,
+		Icon:     asset.Icon,
+	}
+}
+
+// DisplayName resolve display name with language fallback
+//
+// Fallback order: requested language, simplified/traditional Chinese sibling,
+// catalog default language, English, then asset ID.
+func (c *Catalog) DisplayName(asset AssetDefinition, language string) string {
+	for _, lang := range fallbackLanguages(NormalizeLanguage(language), c.DefaultLanguage) {
+		if name, ok := asset.Names[lang]; ok && name != "" {
+			return name
+		}
+	}
+	return asset.ID
+}
+
+// NormalizeLanguage map language tags to supported catalog languages
+func NormalizeLanguage(language string) string {
+	lang := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+	switch lang {
+	case "zh-hant", "zh-tw", "zh-hk", "zh-mo", "zh-hant-tw", "zh-hant-hk":
+		return LanguageChineseTraditional
+	case "zh", "zh-hans", "zh-cn", "zh-sg", "zh-hans-cn":
+		return LanguageChineseSimplified
+	}
+
+	// Strip region for other languages (ko-KR -> ko, en-US -> en)
+	if i := strings.Index(lang, "-"); i > 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// fallbackLanguages build language lookup order
+func fallbackLanguages(language, defaultLanguage string) []string {
+	langs := []string{language}
+	switch language {
+	case LanguageChineseTraditional:
+		langs = append(langs, LanguageChineseSimplified)
+	case LanguageChineseSimplified:
+		langs = append(langs, LanguageChineseTraditional)
+	}
+	return append(langs, defaultLanguage, LanguageEnglish)
+}
@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultCatalog(t *testing.T) {
+	c := Get()
+
+	// 기본 카탈로그에 샘플 자산이 포함되어 있는지 확인
+	expectedAssets := []string{"asset_money", "asset_gold", "item_gem", "item_banana", "asset_silver", "item_apple", "item_fish", "item_branch", "item_horn", "item_maple"}
+	for _, id := range expectedAssets {
+		_, ok := c.Lookup(id)
+		assert.True(t, ok, "Asset %s should exist in default catalog", id)
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	cases := map[string]string{
+		"ko":      "ko",
+		"ko-KR":   "ko",
+		"EN_us":   "en",
+		"zh":      "zh",
+		"zh-CN":   "zh",
+		"zh-Hans": "zh",
+		"zh-Hant": "zh-Hant",
+		"zh-TW":   "zh-Hant",
+		"zh_hk":   "zh-Hant",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, NormalizeLanguage(input), "input %s", input)
+	}
+}
+
+func TestDisplayNameFallback(t *testing.T) {
+	c, err := Parse([]byte(`{
+		"default_language": "ko",
+		"assets": [
+			{"id": "a", "type": "item", "names": {"ko": "가", "en": "A", "zh": "甲"}},
+			{"id": "b", "type": "item", "names": {"zh-Hant": "乙"}},
+			{"id": "c", "type": "item", "names": {"en": "C"}},
+			{"id": "d", "type": "item"}
+		]
+	}`))
+	require.NoError(t, err)
+
+	a, _ := c.Lookup("a")
+	assert.Equal(t, "A", c.DisplayName(a, "en-US"))
+	assert.Equal(t, "甲", c.DisplayName(a, "zh-Hant"), "zh-Hant should fall back to zh")
+	assert.Equal(t, "가", c.DisplayName(a, "ja"), "unknown language should fall back to catalog default")
+
+	b, _ := c.Lookup("b")
+	assert.Equal(t, "乙", c.DisplayName(b, "zh"), "zh should fall back to zh-Hant")
+
+	cc, _ := c.Lookup("c")
+	assert.Equal(t, "C", c.DisplayName(cc, "ko"), "missing default language should fall back to en")
+
+	d, _ := c.Lookup("d")
+	assert.Equal(t, "d", c.DisplayName(d, "ko"), "no names should fall back to asset ID")
+}
+
+func TestParseRejectsDuplicateIDs(t *testing.T) {
+	_, err := Parse([]byte(`{"assets": [{"id": "a"}, {"id": "a"}]}`))
+	assert.Error(t, err)
+}
+
+func TestInitCatalogFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"assets": [{"id": "custom", "type": "item", "decimals": 2, "names": {"en": "Custom"}}]}`), 0o600))
+
+	require.NoError(t, InitCatalog(path))
+	defer func() {
+		catalogMu.Lock()
+		current = nil
+		catalogMu.Unlock()
+	}()
+
+	meta := Get().Metadata("custom", "ko")
+	require.NotNil(t, meta)
+	assert.Equal(t, "Custom", meta.Name)
+	assert.Equal(t, 2, meta.Decimals)
+	assert.Nil(t, Get().Metadata("asset_money", "ko"))
+}
@@ -0,0 +1,195 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"sample-game-backend/internal/config"
+)
+
+// ErrNoCertificates the client CA file holds no PEM certificate
+var ErrNoCertificates = errors.New("no certificates found")
+
+// fileStamp modification time and size used to detect changed files
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reloader server certificate and client CA pool, reloaded when their files change
+// Handshakes always use the last files that loaded successfully.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	interval time.Duration
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	stamps   map[string]fileStamp
+}
+
+// NewReloader load the certificate, key and client CA bundle (optional) of cfg
+func NewReloader(cfg config.TLSConfig) (*Reloader, error) {
+	r := &Reloader{
+		certFile: cfg.CertFile,
+		keyFile:  cfg.KeyFile,
+		caFile:   cfg.ClientCAFile,
+		interval: cfg.ReloadInterval,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MutualTLS client certificates are verified against a CA bundle
+func (r *Reloader) MutualTLS() bool {
+	return r.caFile != ""
+}
+
+// Reload read the files again, keeping the previous certificates when they fail to load
+func (r *Reloader) Reload() error {
+	stamps, err := r.stat()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS key pair: %w", err)
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return fmt.Errorf("parse TLS certificate: %w", err)
+		}
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("read client CA: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("client CA %s: %w", r.caFile, ErrNoCertificates)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCA = pool
+	r.stamps = stamps
+	return nil
+}
+
+// stat modification stamps of the configured files
+func (r *Reloader) stat() (map[string]fileStamp, error) {
+	stamps := make(map[string]fileStamp, 3)
+	for _, path := range []string{r.certFile, r.keyFile, r.caFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", path, err)
+		}
+		stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps, nil
+}
+
+// changed any configured file differs from the files last loaded
+func (r *Reloader) changed() bool {
+	stamps, err := r.stat()
+	if err != nil {
+		// Files being replaced may be missing briefly; retried on the next tick
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for path, stamp := range stamps {
+		if previous, ok := r.stamps[path]; !ok || !previous.modTime.Equal(stamp.modTime) || previous.size != stamp.size {
+			return true
+		}
+	}
+	return false
+}
+
+// Run reload changed files periodically until the context is canceled
+func (r *Reloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.Reload(); err != nil {
+				slog.Error("Reloader", "error", "Failed to reload TLS files, keeping the previous certificates", "err", err)
+				continue
+			}
+			slog.Info("Reloader", "action", "TLS files reloaded", "notAfter", r.Certificate().Leaf.NotAfter)
+		}
+	}
+}
+
+// Certificate current server certificate
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// Check the server certificate is currently valid
+func (r *Reloader) Check(now time.Time) error {
+	leaf := r.Certificate().Leaf
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return fmt.Errorf("TLS certificate valid from %s to %s", leaf.NotBefore.Format(time.RFC3339), leaf.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// TLSConfig server configuration using the current certificates on every handshake
+// With mutual TLS, client certificates are verified when presented; routes that require one
+// reject requests without a verified chain (see middleware.ClientCertMiddleware).
+func (r *Reloader) TLSConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+	}
+	if !r.MutualTLS() {
+		return base
+	}
+
+	// The client CA pool is reloaded too, so each handshake gets a config built from the current files
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		return &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{*r.cert},
+			ClientAuth:   tls.VerifyClientCertIfGiven,
+			ClientCAs:    r.clientCA,
+			NextProtos:   []string{"h2", "http/1.1"},
+		}, nil
+	}
+	return base
+}
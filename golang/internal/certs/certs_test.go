@@ -0,0 +1,148 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// issue CA(parent가 nil이면 자체 서명)가 서명한 인증서와 키
+func issue(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+// writePEM 인증서와 키를 PEM 파일로 저장
+func writePEM(t *testing.T, dir, name string, cert *x509.Certificate, key *ecdsa.PrivateKey) (string, string) {
+	certPath := filepath.Join(dir, name+".crt")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600))
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	keyPath := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certPath, keyPath
+}
+
+func TestReloadOnChange(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := issue(t, "first.example", nil, nil, false)
+	certPath, keyPath := writePEM(t, dir, "server", cert, key)
+
+	reloader, err := NewReloader(config.TLSConfig{CertFile: certPath, KeyFile: keyPath, ReloadInterval: time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, "first.example", reloader.Certificate().Leaf.Subject.CommonName)
+	assert.False(t, reloader.changed())
+	assert.NoError(t, reloader.Check(time.Now()))
+	assert.Error(t, reloader.Check(time.Now().Add(2*time.Hour)))
+
+	// 파일이 바뀌면 새 인증서를 사용
+	cert, key = issue(t, "second.example", nil, nil, false)
+	writePEM(t, dir, "server", cert, key)
+	require.NoError(t, os.Chtimes(certPath, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	assert.True(t, reloader.changed())
+	require.NoError(t, reloader.Reload())
+	served, err := reloader.TLSConfig().GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "second.example", served.Leaf.Subject.CommonName)
+
+	// 잘못된 파일은 거부하고 이전 인증서 유지
+	require.NoError(t, os.WriteFile(certPath, []byte("not a certificate"), 0o600))
+	assert.Error(t, reloader.Reload())
+	assert.Equal(t, "second.example", reloader.Certificate().Leaf.Subject.CommonName)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := issue(t, "ramp-ca", nil, nil, true)
+	caPath, _ := writePEM(t, dir, "ca", ca, caKey)
+	serverCert, serverKey := issue(t, "ramp-server", ca, caKey, false)
+	certPath, keyPath := writePEM(t, dir, "server", serverCert, serverKey)
+	clientCert, clientKey := issue(t, "cross-ramp", ca, caKey, false)
+	otherCA, otherKey := issue(t, "other-ca", nil, nil, true)
+	strangerCert, strangerKey := issue(t, "stranger", otherCA, otherKey, false)
+
+	reloader, err := NewReloader(config.TLSConfig{CertFile: certPath, KeyFile: keyPath, ClientCAFile: caPath, ReloadInterval: time.Second})
+	require.NoError(t, err)
+	require.True(t, reloader.MutualTLS())
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.VerifiedChains) == 0 {
+			w.Write([]byte("anonymous"))
+			return
+		}
+		w.Write([]byte(r.TLS.VerifiedChains[0][0].Subject.CommonName))
+	}))
+	server.TLS = reloader.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	get := func(cert *x509.Certificate, key *ecdsa.PrivateKey) (string, error) {
+		tlsConfig := &tls.Config{RootCAs: roots, ServerName: "ramp-server"}
+		if cert != nil {
+			// 서버가 요청한 CA와 관계없이 항상 인증서를 제출
+			tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return &tls.Certificate{Certificate: [][]byte{cert.Raw}, PrivateKey: key}, nil
+			}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body := make([]byte, 64)
+		n, _ := resp.Body.Read(body)
+		return string(body[:n]), nil
+	}
+
+	// CA가 서명한 클라이언트 인증서는 검증된 체인으로 전달
+	identity, err := get(clientCert, clientKey)
+	require.NoError(t, err)
+	assert.Equal(t, "cross-ramp", identity)
+
+	// 인증서 없는 클라이언트도 연결은 가능 (경로별 미들웨어가 거부)
+	identity, err = get(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "anonymous", identity)
+
+	// 다른 CA의 인증서는 핸드셰이크에서 거부
+	_, err = get(strangerCert, strangerKey)
+	assert.Error(t, err)
+}
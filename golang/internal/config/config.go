@@ -1,14 +1,44 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
 	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Config application configuration
 type Config struct {
 	Port string
-	DB   DBConfig
+	// ShutdownTimeout time in-flight requests get to finish after SIGTERM
+	ShutdownTimeout time.Duration
+	// PreStopDelay time /ready reports draining before the server stops accepting connections
+	PreStopDelay time.Duration
+	// TrustedProxies reverse proxies (IPs or CIDRs, comma-separated) whose X-Forwarded-For gives the client IP;
+	// empty uses the connection address
+	TrustedProxies string
+	DB             DBConfig
+	Catalog        CatalogConfig
+	Enrollment     EnrollmentConfig
+	Rules          RulesConfig
+	Conversion     ConversionConfig
+	Reservation    ReservationConfig
+	OrderExpiry    OrderExpiryConfig
+	Reconcile      ReconcileConfig
+	Receipt        ReceiptConfig
+	HMAC           HMACConfig
+	Tracing        TracingConfig
+	Audit          AuditConfig
+	Admin          AdminConfig
+	RateLimit      RateLimitConfig
+	Session        SessionConfig
+	TLS            TLSConfig
 }
 
 // DBConfig database configuration
@@ -16,15 +46,387 @@ type DBConfig struct {
 	Path string
 }
 
+// CatalogConfig asset catalog configuration
+type CatalogConfig struct {
+	// Path asset catalog file (embedded default catalog is used when empty)
+	Path string
+}
+
+// EnrollmentConfig wallet enrollment configuration
+type EnrollmentConfig struct {
+	// Required reject validate requests whose user_address is not the enrolled wallet
+	Required bool
+	// JWTSecret HMAC key for verifying CROSS_AUTH_JWT (HS256)
+	JWTSecret string
+	// JWTPublicKeyPath PEM public key for verifying CROSS_AUTH_JWT (RS256/ES256), takes precedence over JWTSecret
+	JWTPublicKeyPath string
+	// JWTIssuer required iss claim of CROSS_AUTH_JWT
+	JWTIssuer string
+	// JWTAudience required aud claim of CROSS_AUTH_JWT
+	JWTAudience string
+	// MessageTTL maximum age of a signed enrollment message
+	MessageTTL time.Duration
+}
+
+// RulesConfig business rules configuration
+type RulesConfig struct {
+	// Path rules file evaluated in the validate path (no rules when empty)
+	Path string
+}
+
+// ConversionConfig conversion table configuration
+type ConversionConfig struct {
+	// Path conversion rules between in-game assets and tokens (rates are not checked when empty)
+	Path string
+}
+
+// ReservationConfig pending credit configuration for disassemble orders
+type ReservationConfig struct {
+	// TTL time a pending credit waits for the result webhook before it is released
+	TTL time.Duration
+}
+
+// OrderExpiryConfig background expiry of orders without a result webhook
+type OrderExpiryConfig struct {
+	// Deadline time a validated order waits for its result before it expires
+	Deadline time.Duration
+	// Interval time between sweeps
+	Interval time.Duration
+	// Refund return assets deducted by expired assemble orders
+	Refund bool
+	// Retention time finished orders are kept before removal (kept forever when zero)
+	Retention time.Duration
+}
+
+// ReconcileConfig reconciliation against the CROSS RAMP Order Information Query API
+type ReconcileConfig struct {
+	// APIURL CROSS RAMP API endpoint
+	APIURL string
+	// Network chain network of the orders (reconciliation is disabled when empty)
+	Network string
+	// Interval time between reconciliation runs
+	Interval time.Duration
+	// MinAge time a validated order waits for its result webhook before it is queried
+	MinAge time.Duration
+	// Lookback how far back expired orders are still queried
+	Lookback time.Duration
+}
+
+// ReceiptConfig result webhook receipt verification
+type ReceiptConfig struct {
+	// Verify check tx_hash and token transfer logs of result receipts against the stored order
+	Verify bool
+	// RPCURL Ethereum JSON-RPC endpoint the posted receipt is confirmed against (empty disables)
+	RPCURL string
+	// Confirmations blocks required on top of the receipt's block, including it
+	Confirmations uint64
+	// ConfirmTimeout time a result webhook waits for the confirmations
+	ConfirmTimeout time.Duration
+	// MinAmount orders whose token amount is below this are not confirmed on-chain (0 confirms every order)
+	MinAmount uint64
+}
+
+// HMACConfig X-HMAC-SIGNATURE verification of CROSS RAMP requests
+type HMACConfig struct {
+	// Key base64url HMAC key shared with CROSS RAMP (empty disables verification)
+	Key string
+}
+
+// TracingConfig OpenTelemetry tracing
+type TracingConfig struct {
+	// Exporter span exporter: "otlp", "stdout" or "none"
+	// The OTLP exporter reads its endpoint from OTEL_EXPORTER_OTLP_ENDPOINT
+	Exporter    string
+	ServiceName string
+}
+
+// AuditConfig validator signature audit log
+type AuditConfig struct {
+	// Path hash-chained JSON lines file every issued signature is appended to (empty disables auditing)
+	Path string
+}
+
+// AdminConfig support staff admin API
+type AdminConfig struct {
+	// APIKeys "operator:key" pairs separated by commas (empty disables the admin API)
+	APIKeys string
+}
+
+// RateLimit token bucket refilled at Rate tokens per second up to Burst tokens (a zero rate disables it)
+type RateLimit struct {
+	Rate  float64
+	Burst uint64
+}
+
+// RateLimitConfig rate limits of the public API by session ID, client IP and project
+type RateLimitConfig struct {
+	Session RateLimit
+	IP      RateLimit
+	Project RateLimit
+	// WebhookIP also limit the CROSS RAMP webhooks by client IP (they share the few IPs of the CROSS RAMP backend)
+	WebhookIP bool
+	// IdleTTL time an unused bucket is kept before it is dropped
+	IdleTTL time.Duration
+}
+
+// SessionConfig admission control of sessions created from X-Dapp-SessionID
+type SessionConfig struct {
+	// RequireKnown create only sessions that are enrolled or recognized by the asset provider
+	RequireKnown bool
+	// MaxSessions stored sessions before the least recently used idle one is evicted (0 is unlimited)
+	MaxSessions uint64
+	// IdleTTL time an unused session is kept (0 keeps sessions forever)
+	IdleTTL time.Duration
+}
+
+// TLSConfig HTTPS termination and mutual TLS of CROSS RAMP requests
+type TLSConfig struct {
+	// CertFile, KeyFile PEM server certificate chain and key (both empty serves plain HTTP)
+	CertFile string
+	KeyFile  string
+	// ClientCAFile PEM CA bundle of the CROSS RAMP client certificate; requires it on /api/validate and /api/result
+	ClientCAFile string
+	// ClientNames accepted client certificate names (common name or DNS SAN), comma-separated; empty accepts any name
+	ClientNames string
+	// ReloadInterval how often the files are checked for changes
+	ReloadInterval time.Duration
+}
+
+// ResultRetryWindow time CROSS RAMP keeps retrying a result webhook, per the integration guide
+// Orders must outlive it so a late result still finds its order.
+const ResultRetryWindow = 12 * time.Hour
+
 // InitConfig initialize configuration
 func InitConfig() *Config {
 	// Initialize random seed
 	rand.Seed(time.Now().UnixNano())
 
 	return &Config{
-		Port: ":8080",
+		Port:            ":8080",
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		PreStopDelay:    getEnvDuration("SHUTDOWN_PRESTOP_DELAY", 0),
+		TrustedProxies:  getEnv("TRUSTED_PROXIES", ""),
 		DB: DBConfig{
 			Path: "./session_db",
 		},
+		Catalog: CatalogConfig{
+			Path: getEnv("ASSET_CATALOG_PATH", ""),
+		},
+		Enrollment: EnrollmentConfig{
+			Required:         getEnvBool("ENROLLMENT_REQUIRED", false),
+			JWTSecret:        getEnv("CROSS_AUTH_JWT_SECRET", ""),
+			JWTPublicKeyPath: getEnv("CROSS_AUTH_JWT_PUBLIC_KEY_PATH", ""),
+			JWTIssuer:        getEnv("CROSS_AUTH_JWT_ISSUER", ""),
+			JWTAudience:      getEnv("CROSS_AUTH_JWT_AUDIENCE", ""),
+			MessageTTL:       getEnvDuration("ENROLLMENT_MESSAGE_TTL", 10*time.Minute),
+		},
+		Rules: RulesConfig{
+			Path: getEnv("RULES_PATH", ""),
+		},
+		Conversion: ConversionConfig{
+			Path: getEnv("CONVERSION_RULES_PATH", ""),
+		},
+		Reservation: ReservationConfig{
+			TTL: getEnvDuration("RESERVATION_TTL", time.Hour),
+		},
+		OrderExpiry: OrderExpiryConfig{
+			Deadline:  getEnvDuration("ORDER_EXPIRY_DEADLINE", time.Hour),
+			Interval:  getEnvDuration("ORDER_SWEEP_INTERVAL", time.Minute),
+			Refund:    getEnvBool("ORDER_EXPIRY_REFUND", false),
+			Retention: getEnvDuration("ORDER_RETENTION", 0),
+		},
+		Reconcile: ReconcileConfig{
+			APIURL:   getEnv("CROSS_RAMP_API_URL", "https://cross-ramp-api.crosstoken.io"),
+			Network:  getEnv("CROSS_RAMP_NETWORK", ""),
+			Interval: getEnvDuration("RECONCILE_INTERVAL", 5*time.Minute),
+			MinAge:   getEnvDuration("RECONCILE_MIN_AGE", 5*time.Minute),
+			Lookback: getEnvDuration("RECONCILE_LOOKBACK", 24*time.Hour),
+		},
+		Receipt: ReceiptConfig{
+			Verify:         getEnvBool("RECEIPT_VERIFICATION", false),
+			RPCURL:         getEnv("RECEIPT_RPC_URL", ""),
+			Confirmations:  getEnvUint64("RECEIPT_CONFIRMATIONS", 12),
+			ConfirmTimeout: getEnvDuration("RECEIPT_CONFIRM_TIMEOUT", 2*time.Minute),
+			MinAmount:      getEnvUint64("RECEIPT_RPC_MIN_AMOUNT", 0),
+		},
+		HMAC: HMACConfig{
+			Key: getEnv("HMAC_KEY", ""),
+		},
+		Tracing: TracingConfig{
+			Exporter:    getEnv("OTEL_TRACES_EXPORTER", "none"),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "sample-game-backend"),
+		},
+		Audit: AuditConfig{
+			Path: getEnv("AUDIT_LOG_PATH", "./signature_audit.jsonl"),
+		},
+		Admin: AdminConfig{
+			APIKeys: getEnv("ADMIN_API_KEYS", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Session: RateLimit{
+				Rate:  getEnvFloat("RATE_LIMIT_SESSION_RPS", 5),
+				Burst: getEnvUint64("RATE_LIMIT_SESSION_BURST", 20),
+			},
+			IP: RateLimit{
+				Rate:  getEnvFloat("RATE_LIMIT_IP_RPS", 20),
+				Burst: getEnvUint64("RATE_LIMIT_IP_BURST", 50),
+			},
+			Project: RateLimit{
+				Rate:  getEnvFloat("RATE_LIMIT_PROJECT_RPS", 0),
+				Burst: getEnvUint64("RATE_LIMIT_PROJECT_BURST", 200),
+			},
+			WebhookIP: getEnvBool("RATE_LIMIT_WEBHOOK_IP", false),
+			IdleTTL:   getEnvDuration("RATE_LIMIT_IDLE_TTL", 10*time.Minute),
+		},
+		Session: SessionConfig{
+			RequireKnown: getEnvBool("SESSION_REQUIRE_KNOWN", false),
+			MaxSessions:  getEnvUint64("SESSION_MAX", 100000),
+			IdleTTL:      getEnvDuration("SESSION_IDLE_TTL", 24*time.Hour),
+		},
+		TLS: TLSConfig{
+			CertFile:       getEnv("TLS_CERT_FILE", ""),
+			KeyFile:        getEnv("TLS_KEY_FILE", ""),
+			ClientCAFile:   getEnv("TLS_CLIENT_CA_FILE", ""),
+			ClientNames:    getEnv("TLS_CLIENT_NAMES", ""),
+			ReloadInterval: getEnvDuration("TLS_RELOAD_INTERVAL", 30*time.Second),
+		},
+	}
+}
+
+// TrustedProxyList entries of TrustedProxies (empty trusts no proxy)
+func (c *Config) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(c.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// Validate report invalid settings (nil when the configuration is usable)
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Port == "" {
+		errs = append(errs, errors.New("port is empty"))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if c.PreStopDelay < 0 {
+		errs = append(errs, errors.New("SHUTDOWN_PRESTOP_DELAY must not be negative"))
+	}
+	for _, proxy := range c.TrustedProxyList() {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("TRUSTED_PROXIES entry %q is not an IP or CIDR", proxy))
+		}
+	}
+	if (c.Enrollment.JWTSecret != "" || c.Enrollment.JWTPublicKeyPath != "") && (c.Enrollment.JWTIssuer == "" || c.Enrollment.JWTAudience == "") {
+		errs = append(errs, errors.New("CROSS_AUTH_JWT verification needs CROSS_AUTH_JWT_ISSUER and CROSS_AUTH_JWT_AUDIENCE"))
+	}
+	if c.Reservation.TTL <= 0 {
+		errs = append(errs, errors.New("RESERVATION_TTL must be positive"))
+	}
+	if c.OrderExpiry.Deadline <= 0 || c.OrderExpiry.Interval <= 0 {
+		errs = append(errs, errors.New("ORDER_EXPIRY_DEADLINE and ORDER_SWEEP_INTERVAL must be positive"))
+	}
+	if c.OrderExpiry.Retention > 0 && c.OrderExpiry.Retention < ResultRetryWindow {
+		errs = append(errs, fmt.Errorf("ORDER_RETENTION must be at least the %s result retry window", ResultRetryWindow))
+	}
+	if c.Reconcile.Network != "" && (c.Reconcile.APIURL == "" || c.Reconcile.Interval <= 0 || c.Reconcile.Lookback <= 0) {
+		errs = append(errs, errors.New("reconciliation needs CROSS_RAMP_API_URL and a positive RECONCILE_INTERVAL and RECONCILE_LOOKBACK"))
+	}
+	if c.Receipt.RPCURL != "" && c.Receipt.ConfirmTimeout < 0 {
+		errs = append(errs, errors.New("RECEIPT_CONFIRM_TIMEOUT must not be negative"))
+	}
+	for _, limit := range []struct {
+		name string
+		RateLimit
+	}{{"SESSION", c.RateLimit.Session}, {"IP", c.RateLimit.IP}, {"PROJECT", c.RateLimit.Project}} {
+		if limit.Rate < 0 || (limit.Rate > 0 && limit.Burst == 0) {
+			errs = append(errs, fmt.Errorf("RATE_LIMIT_%s_RPS must not be negative and needs a positive RATE_LIMIT_%s_BURST", limit.name, limit.name))
+		}
+	}
+	if c.Session.MaxSessions > math.MaxInt32 {
+		errs = append(errs, fmt.Errorf("SESSION_MAX must be at most %d", math.MaxInt32))
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		errs = append(errs, errors.New("TLS_CLIENT_CA_FILE needs TLS_CERT_FILE and TLS_KEY_FILE"))
+	}
+	if c.TLS.CertFile != "" && c.TLS.ReloadInterval <= 0 {
+		errs = append(errs, errors.New("TLS_RELOAD_INTERVAL must be positive"))
+	}
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "", "none", "otlp", "stdout", "console":
+	default:
+		errs = append(errs, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q", c.Tracing.Exporter))
+	}
+	return errors.Join(errs...)
+}
+
+// getEnv return environment variable or default value
+func getEnv(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvBool return boolean environment variable or default value
+func getEnvBool(key string, defaultValue bool) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("InitConfig", "warning", "Invalid boolean, using default", "key", key, "value", value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvDuration return duration environment variable (e.g. "10m") or default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("InitConfig", "warning", "Invalid duration, using default", "key", key, "value", value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvUint64 return unsigned integer environment variable or default value
+func getEnvUint64(key string, defaultValue uint64) uint64 {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		slog.Warn("InitConfig", "warning", "Invalid unsigned integer, using default", "key", key, "value", value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvFloat return decimal environment variable or default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		slog.Warn("InitConfig", "warning", "Invalid number, using default", "key", key, "value", value)
+		return defaultValue
 	}
+	return parsed
 }
@@ -0,0 +1,168 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"sample-game-backend/internal/models"
+)
+
+// Error codes
+const (
+	CodeUnknownToken         = "UNKNOWN_TOKEN"
+	CodeExchangeRateMismatch = "EXCHANGE_RATE_MISMATCH"
+)
+
+// Rule registered conversion between in-game assets and tokens
+//
+// Assets are the in-game side and Tokens the on-chain side of one unit of the
+// conversion. An intent matches when every amount is the same positive multiple
+// of the rule's amounts. Multi-input recipes list several assets.
+type Rule struct {
+	ID     string             `json:"id"`
+	Assets []models.PairAsset `json:"assets"`
+	Tokens []models.PairAsset `json:"tokens"`
+	// Directions intent types allowed for the rule (assemble and disassemble when empty)
+	Directions []string `json:"directions,omitempty"`
+}
+
+// Table conversion rule table
+type Table struct {
+	Rules []Rule `json:"rules"`
+
+	tokens map[string]bool
+}
+
+// Mismatch intent rejected by the conversion table
+type Mismatch struct {
+	Code    string
+	Message string
+}
+
+// Error implement error interface
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("%s: %s", m.Code, m.Message)
+}
+
+// Parse parse conversion table JSON
+func Parse(data []byte) (*Table, error) {
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid conversion table: %w", err)
+	}
+
+	t.tokens = make(map[string]bool)
+	for i, rule := range t.Rules {
+		if len(rule.Assets) == 0 || len(rule.Tokens) == 0 {
+			return nil, fmt.Errorf("invalid conversion table: rule %d needs assets and tokens", i)
+		}
+		for _, item := range append(append([]models.PairAsset{}, rule.Assets...), rule.Tokens...) {
+			if item.AssetID == "" || item.Amount == 0 {
+				return nil, fmt.Errorf("invalid conversion table: rule %d has an item without id or amount", i)
+			}
+		}
+		for _, direction := range rule.Directions {
+			if direction != models.IntentTypeAssemble && direction != models.IntentTypeDisassemble {
+				return nil, fmt.Errorf("invalid conversion table: rule %d has unknown direction %s", i, direction)
+			}
+		}
+		for _, token := range rule.Tokens {
+			t.tokens[itemKey(token)] = true
+		}
+	}
+
+	return &t, nil
+}
+
+// Load load conversion table from file
+func Load(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conversion table: %w", err)
+	}
+	return Parse(data)
+}
+
+// Validate check intent amounts against the registered conversion rules
+// Returns *Mismatch for unknown tokens or amounts that match no rule
+func (t *Table) Validate(intent models.ExchangeIntent) error {
+	// assemble: assets -> tokens, disassemble: tokens -> assets
+	assets, tokens := intent.From, intent.To
+	if intent.Type == models.IntentTypeDisassemble {
+		assets, tokens = intent.To, intent.From
+	}
+
+	for _, token := range tokens {
+		if !t.tokens[itemKey(token)] {
+			return &Mismatch{Code: CodeUnknownToken, Message: fmt.Sprintf("token %s (%s) is not registered", token.AssetID, token.Type)}
+		}
+	}
+
+	for _, rule := range t.Rules {
+		if !rule.allows(intent.Type) {
+			continue
+		}
+		if multiple(rule.Assets, assets, rule.Tokens, tokens) > 0 {
+			return nil
+		}
+	}
+
+	return &Mismatch{Code: CodeExchangeRateMismatch, Message: fmt.Sprintf("%s intent amounts do not match any conversion rule", intent.Type)}
+}
+
+// allows report whether rule can be used for intent type
+func (r Rule) allows(intentType string) bool {
+	if len(r.Directions) == 0 {
+		return true
+	}
+	for _, direction := range r.Directions {
+		if direction == intentType {
+			return true
+		}
+	}
+	return false
+}
+
+// multiple return k when every intent amount is k times the rule amount (0 when not matching)
+func multiple(ruleAssets, assets, ruleTokens, tokens []models.PairAsset) uint64 {
+	var k uint64
+	for _, side := range [][2][]models.PairAsset{{ruleAssets, assets}, {ruleTokens, tokens}} {
+		want, got := aggregate(side[0]), aggregate(side[1])
+		if len(want) != len(got) {
+			return 0
+		}
+		for key, unit := range want {
+			amount, ok := got[key]
+			if !ok || amount%unit != 0 {
+				return 0
+			}
+			m := amount / unit
+			if k == 0 {
+				k = m
+			} else if k != m {
+				return 0
+			}
+		}
+	}
+	return k
+}
+
+// aggregate sum amounts per item
+func aggregate(items []models.PairAsset) map[string]uint64 {
+	amounts := make(map[string]uint64, len(items))
+	for _, item := range items {
+		amounts[itemKey(item)] += uint64(item.Amount)
+	}
+	return amounts
+}
+
+// itemKey identify item by type and ID (token addresses are compared case-insensitively)
+func itemKey(item models.PairAsset) string {
+	id := item.AssetID
+	if strings.HasPrefix(id, "0x") || strings.HasPrefix(id, "0X") {
+		id = strings.ToLower(id)
+	}
+	return item.Type + ":" + id
+}
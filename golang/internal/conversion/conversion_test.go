@@ -0,0 +1,113 @@
+package conversion
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testToken   = "0x1234567890123456789012345678901234567890"
+	testNFT     = "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd"
+	testUnknown = "0x0000000000000000000000000000000000000001"
+)
+
+// mismatchCode 불일치 코드 추출
+func mismatchCode(t *testing.T, err error) string {
+	var mismatch *Mismatch
+	require.True(t, errors.As(err, &mismatch), "expected mismatch, got %v", err)
+	return mismatch.Code
+}
+
+func loadExampleTable(t *testing.T) *Table {
+	data, err := os.ReadFile("../../conversion_rules.example.json")
+	require.NoError(t, err)
+	table, err := Parse(data)
+	require.NoError(t, err)
+	return table
+}
+
+func TestValidateAssemble(t *testing.T) {
+	table := loadExampleTable(t)
+
+	// 1000 asset_money -> 1 토큰의 배수는 허용
+	intent := models.ExchangeIntent{
+		Type: "assemble",
+		From: []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 3000}},
+		To:   []models.PairAsset{{Type: "erc20", AssetID: testToken, Amount: 3}},
+	}
+	assert.NoError(t, table.Validate(intent))
+
+	// 비율이 맞지 않으면 거부
+	intent.To[0].Amount = 4
+	assert.Equal(t, CodeExchangeRateMismatch, mismatchCode(t, table.Validate(intent)))
+
+	// 단위 배수가 아닌 경우 거부
+	intent.From[0].Amount = 1500
+	intent.To[0].Amount = 1
+	assert.Equal(t, CodeExchangeRateMismatch, mismatchCode(t, table.Validate(intent)))
+
+	// 등록되지 않은 토큰은 거부
+	intent.To[0].AssetID = testUnknown
+	assert.Equal(t, CodeUnknownToken, mismatchCode(t, table.Validate(intent)))
+}
+
+func TestValidateMultiInputRecipe(t *testing.T) {
+	table := loadExampleTable(t)
+
+	intent := models.ExchangeIntent{
+		Type: "assemble",
+		From: []models.PairAsset{
+			{Type: "asset", AssetID: "asset_gold", Amount: 1000},
+			{Type: "asset", AssetID: "item_gem", Amount: 20},
+		},
+		To: []models.PairAsset{{Type: "erc1155", AssetID: "0xABCDEFabcdefABCDEFabcdefABCDEFabcdefABCD", Amount: 2}},
+	}
+	assert.NoError(t, table.Validate(intent), "token addresses should be compared case-insensitively")
+
+	// 재료 하나가 빠지면 거부
+	intent.From = intent.From[:1]
+	assert.Equal(t, CodeExchangeRateMismatch, mismatchCode(t, table.Validate(intent)))
+
+	// 분해 방향이 허용되지 않은 레시피
+	disassemble := models.ExchangeIntent{
+		Type: "disassemble",
+		From: []models.PairAsset{{Type: "erc1155", AssetID: testNFT, Amount: 1}},
+		To: []models.PairAsset{
+			{Type: "asset", AssetID: "item_gem", Amount: 10},
+			{Type: "asset", AssetID: "asset_gold", Amount: 500},
+		},
+	}
+	assert.Equal(t, CodeExchangeRateMismatch, mismatchCode(t, table.Validate(disassemble)))
+}
+
+func TestValidateDisassemble(t *testing.T) {
+	table := loadExampleTable(t)
+
+	intent := models.ExchangeIntent{
+		Type: "disassemble",
+		From: []models.PairAsset{{Type: "erc20", AssetID: testToken, Amount: 2}},
+		To:   []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 2000}},
+	}
+	assert.NoError(t, table.Validate(intent))
+
+	intent.To[0].Amount = 2001
+	assert.Equal(t, CodeExchangeRateMismatch, mismatchCode(t, table.Validate(intent)))
+}
+
+func TestParseRejectsInvalidTable(t *testing.T) {
+	invalid := []string{
+		`{"rules": [{"id": "a", "assets": [], "tokens": [{"type": "erc20", "id": "0x1", "amount": 1}]}]}`,
+		`{"rules": [{"id": "a", "assets": [{"type": "asset", "id": "x", "amount": 0}], "tokens": [{"type": "erc20", "id": "0x1", "amount": 1}]}]}`,
+		`{"rules": [{"id": "a", "assets": [{"type": "asset", "id": "x", "amount": 1}], "tokens": [{"type": "erc20", "id": "0x1", "amount": 1}], "directions": ["swap"]}]}`,
+	}
+	for _, config := range invalid {
+		_, err := Parse([]byte(config))
+		assert.Error(t, err, config)
+	}
+}
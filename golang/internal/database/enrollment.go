@@ -0,0 +1,82 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// ErrWalletAlreadyEnrolled session already has a different wallet enrolled
+var ErrWalletAlreadyEnrolled = errors.New("a different wallet is already enrolled")
+
+// WalletEnrollment wallet-to-character mapping structure
+type WalletEnrollment struct {
+	SessionID     string `json:"session_id"`
+	WalletAddress string `json:"wallet_address"`
+	Method        string `json:"method"`
+	EnrolledAt    string `json:"enrolled_at"`
+}
+
+// StoreWalletEnrollment link verified wallet address to session
+// Enrolling the same wallet again refreshes the enrollment. A different wallet replaces the current one only
+// when replaces names it (the change was approved by that wallet or an operator); otherwise ErrWalletAlreadyEnrolled.
+func StoreWalletEnrollment(sessionID, walletAddress, method, replaces string) (*WalletEnrollment, error) {
+	enrollment := &WalletEnrollment{
+		SessionID:     sessionID,
+		WalletAddress: walletAddress,
+		Method:        method,
+		EnrolledAt:    time.Now().Format(time.RFC3339),
+	}
+
+	database, err := GetDB()
+	if err != nil {
+		slog.Error("StoreWalletEnrollment", "error", "Failed to get database", "err", err)
+		return nil, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	// Checked under the write lock so two enrollments cannot both claim an unenrolled session
+	raw, err := txn.First("wallet_enrollment", "id", sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if raw != nil {
+		current := raw.(*WalletEnrollment).WalletAddress
+		if !strings.EqualFold(current, walletAddress) && !strings.EqualFold(current, replaces) {
+			return nil, fmt.Errorf("%w: session %s is enrolled with %s", ErrWalletAlreadyEnrolled, sessionID, current)
+		}
+	}
+
+	if err := txn.Insert("wallet_enrollment", enrollment); err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+	slog.Info("StoreWalletEnrollment", "sessionID", sessionID, "walletAddress", walletAddress, "method", method, "action", "committed")
+	return enrollment, nil
+}
+
+// GetWalletEnrollment get wallet enrollment of session (nil if session is not enrolled)
+func GetWalletEnrollment(sessionID string) (*WalletEnrollment, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	raw, err := txn.First("wallet_enrollment", "id", sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	return raw.(*WalletEnrollment), nil
+}
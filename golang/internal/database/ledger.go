@@ -0,0 +1,94 @@
+package database
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+)
+
+// Ledger sources
+const (
+	// LedgerSourceValidate assets deducted by an assemble validate
+	LedgerSourceValidate = "validate"
+	// LedgerSourceResult assets credited (or deducted again) by a result
+	LedgerSourceResult = "result"
+	// LedgerSourceRefund deducted assets returned for a rejected or expired order
+	LedgerSourceRefund = "refund"
+	// LedgerSourceAdjustment manual credit or debit by an operator
+	LedgerSourceAdjustment = "adjustment"
+)
+
+// ledgerSeq last ledger entry ID
+var ledgerSeq atomic.Uint64
+
+// LedgerRef why a balance changed, copied to every ledger entry of the change
+type LedgerRef struct {
+	Source string
+	// Reference order UUID of the change (empty for adjustments)
+	Reference string
+	// Operator support staff who applied an adjustment
+	Operator string
+	Reason   string
+}
+
+// LedgerEntry balance change of one asset
+type LedgerEntry struct {
+	ID        uint64 `json:"id"`
+	SessionID string `json:"session_id"`
+	AccountID string `json:"account_id"`
+	AssetID   string `json:"asset_id"`
+	// Delta signed amount of the change
+	Delta int64 `json:"delta"`
+	// Balance balance after the change
+	Balance   string    `json:"balance"`
+	Source    string    `json:"source"`
+	Reference string    `json:"reference,omitempty"`
+	Operator  string    `json:"operator,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// insertLedgerTxn record a balance change within write transaction
+func insertLedgerTxn(txn *memdb.Txn, sessionID, accountID, assetID string, delta int64, balance string, ref LedgerRef) error {
+	return txn.Insert("ledger", &LedgerEntry{
+		ID:        ledgerSeq.Add(1),
+		SessionID: sessionID,
+		AccountID: accountID,
+		AssetID:   assetID,
+		Delta:     delta,
+		Balance:   balance,
+		Source:    ref.Source,
+		Reference: ref.Reference,
+		Operator:  ref.Operator,
+		Reason:    ref.Reason,
+		CreatedAt: time.Now(),
+	})
+}
+
+// ListLedger list balance changes made through the session, oldest first
+// Changes of account-scoped assets are listed under the session that made them.
+func ListLedger(sessionID string) ([]*LedgerEntry, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	it, err := txn.Get("ledger", "session", sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*LedgerEntry
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		entries = append(entries, obj.(*LedgerEntry))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
@@ -1,16 +1,23 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand"
+	"math"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
 
+	"sample-game-backend/internal/catalog"
+	"sample-game-backend/internal/metrics"
 	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/provider"
 
 	"github.com/hashicorp/go-memdb"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // DB global variables
@@ -18,6 +25,20 @@ var (
 	db     *memdb.MemDB
 	dbOnce sync.Once
 	dbInit bool
+
+	// assetProvider loads unknown sessions (demo provider with random balances by default)
+	assetProvider provider.AssetProvider = provider.NewDemoProvider()
+	providerMu    sync.RWMutex
+)
+
+// Database errors
+var (
+	ErrNotInitialized      = errors.New("database not initialized")
+	ErrAssetNotFound       = errors.New("asset not found")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrCorruptBalance      = errors.New("invalid balance format")
+	ErrAmountOutOfRange    = errors.New("amount out of range")
+	ErrSessionNotFound     = errors.New("session not found")
 )
 
 // InitDB initialize database (singleton pattern)
@@ -35,10 +56,108 @@ func InitDB() error {
 							Unique:  true,
 							Indexer: &memdb.StringFieldIndex{Field: "SessionID"},
 						},
+						"account": {
+							Name:    "account",
+							Unique:  false,
+							Indexer: &memdb.StringFieldIndex{Field: "AccountID"},
+						},
+					},
+				},
+				"account_assets": {
+					Name: "account_assets",
+					Indexes: map[string]*memdb.IndexSchema{
+						"id": {
+							Name:    "id",
+							Unique:  true,
+							Indexer: &memdb.StringFieldIndex{Field: "AccountID"},
+						},
 					},
 				},
 				"uuid_mapping": {
 					Name: "uuid_mapping",
+					Indexes: map[string]*memdb.IndexSchema{
+						"id": {
+							Name:    "id",
+							Unique:  true,
+							Indexer: &memdb.StringFieldIndex{Field: "UUID"},
+						},
+						"status": {
+							Name:         "status",
+							Unique:       false,
+							AllowMissing: true,
+							Indexer:      &memdb.StringFieldIndex{Field: "Status"},
+						},
+						"session": {
+							Name:         "session",
+							Unique:       false,
+							AllowMissing: true,
+							Indexer:      &memdb.StringFieldIndex{Field: "SessionID"},
+						},
+						"tx_hash": {
+							Name:         "tx_hash",
+							Unique:       false,
+							AllowMissing: true,
+							Indexer:      &memdb.StringFieldIndex{Field: "TxHash", Lowercase: true},
+						},
+					},
+				},
+				"ledger": {
+					Name: "ledger",
+					Indexes: map[string]*memdb.IndexSchema{
+						"id": {
+							Name:    "id",
+							Unique:  true,
+							Indexer: &memdb.UintFieldIndex{Field: "ID"},
+						},
+						"session": {
+							Name:    "session",
+							Unique:  false,
+							Indexer: &memdb.StringFieldIndex{Field: "SessionID"},
+						},
+					},
+				},
+				"order_usage": {
+					Name: "order_usage",
+					Indexes: map[string]*memdb.IndexSchema{
+						"id": {
+							Name:    "id",
+							Unique:  true,
+							Indexer: &memdb.StringFieldIndex{Field: "UUID"},
+						},
+						"session": {
+							Name:    "session",
+							Unique:  false,
+							Indexer: &memdb.StringFieldIndex{Field: "SessionID"},
+						},
+						"project": {
+							Name:    "project",
+							Unique:  false,
+							Indexer: &memdb.StringFieldIndex{Field: "ProjectID"},
+						},
+					},
+				},
+				"reservation": {
+					Name: "reservation",
+					Indexes: map[string]*memdb.IndexSchema{
+						"id": {
+							Name:    "id",
+							Unique:  true,
+							Indexer: &memdb.StringFieldIndex{Field: "UUID"},
+						},
+						"session": {
+							Name:    "session",
+							Unique:  false,
+							Indexer: &memdb.StringFieldIndex{Field: "SessionID"},
+						},
+						"status": {
+							Name:    "status",
+							Unique:  false,
+							Indexer: &memdb.StringFieldIndex{Field: "Status"},
+						},
+					},
+				},
+				"order_tombstone": {
+					Name: "order_tombstone",
 					Indexes: map[string]*memdb.IndexSchema{
 						"id": {
 							Name:    "id",
@@ -47,6 +166,16 @@ func InitDB() error {
 						},
 					},
 				},
+				"wallet_enrollment": {
+					Name: "wallet_enrollment",
+					Indexes: map[string]*memdb.IndexSchema{
+						"id": {
+							Name:    "id",
+							Unique:  true,
+							Indexer: &memdb.StringFieldIndex{Field: "SessionID"},
+						},
+					},
+				},
 			},
 		}
 
@@ -65,248 +194,493 @@ func InitDB() error {
 // GetDB return database instance (check initialization)
 func GetDB() (*memdb.MemDB, error) {
 	if !dbInit {
-		return nil, fmt.Errorf("database not initialized")
+		return nil, ErrNotInitialized
 	}
 	return db, nil
 }
 
+// Ping check the database accepts write transactions
+func Ping() error {
+	database, err := GetDB()
+	if err != nil {
+		return err
+	}
+	txn := database.Txn(true)
+	txn.Abort()
+	return nil
+}
+
 // CloseDB close database connection
 func CloseDB() error {
 	// memdb is in-memory, so no additional cleanup is needed
 	return nil
 }
 
-// generateRandomAssets generate random assets
-func generateRandomAssets() map[string]string {
-	assets := make(map[string]string)
-	baseAmount := 100000000
-	// Generate asset_money randomly (1000 ~ 5000)
-	moneyAmount := rand.Intn(baseAmount) + 1000
-	assets["asset_money"] = strconv.Itoa(moneyAmount)
+// SetAssetProvider set the provider used to load unknown sessions
+func SetAssetProvider(p provider.AssetProvider) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	assetProvider = p
+}
 
-	// Generate asset_gold randomly (500 ~ 3000)
-	goldAmount := rand.Intn(baseAmount) + 500
-	assets["asset_gold"] = strconv.Itoa(goldAmount)
+// getAssetProvider return configured asset provider
+func getAssetProvider() provider.AssetProvider {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+	return assetProvider
+}
 
-	// Generate item_gem randomly (500 ~ 3000)
-	gemAmount := rand.Intn(baseAmount) + 500
-	assets["item_gem"] = strconv.Itoa(gemAmount)
+// newSessionAssets create session asset information from player profile
+func newSessionAssets(sessionID string, player *provider.Player) *models.SessionAssets {
+	now := time.Now().Format(time.RFC3339)
+	accountID := player.AccountID
+	if accountID == "" {
+		accountID = sessionID
+	}
 
-	// Generate item_banana randomly (500 ~ 3000)
-	bananaAmount := rand.Intn(baseAmount) + 500
-	assets["item_banana"] = strconv.Itoa(bananaAmount)
+	assets := make(map[string]string, len(player.Assets))
+	for id, balance := range player.Assets {
+		assets[id] = balance
+	}
 
-	// Generate asset_silver randomly (500 ~ 3000)
-	silverAmount := rand.Intn(baseAmount) + 500
-	assets["asset_silver"] = strconv.Itoa(silverAmount)
+	return &models.SessionAssets{
+		SessionID:     sessionID,
+		AccountID:     accountID,
+		Name:          player.Name,
+		ImageURL:      player.ImageURL,
+		WalletAddress: player.WalletAddress,
+		Server:        player.Server,
+		Assets:        assets,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
 
-	// Generate item_apple randomly (500 ~ 3000)
-	appleAmount := rand.Intn(baseAmount) + 500
-	assets["item_apple"] = strconv.Itoa(appleAmount)
+// newAccountAssets create account asset information
+func newAccountAssets(accountID string, common map[string]string) *models.AccountAssets {
+	now := time.Now().Format(time.RFC3339)
+	balances := make(map[string]string, len(common))
+	for id, balance := range common {
+		balances[id] = balance
+	}
 
-	// Generate item_fish randomly (500 ~ 3000)
-	fishAmount := rand.Intn(baseAmount) + 500
-	assets["item_fish"] = strconv.Itoa(fishAmount)
+	return &models.AccountAssets{
+		AccountID: accountID,
+		Common:    balances,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// cloneSessionAssets copy session assets so stored objects are never modified in place
+func cloneSessionAssets(src *models.SessionAssets) *models.SessionAssets {
+	dst := *src
+	dst.Assets = make(map[string]string, len(src.Assets))
+	for id, balance := range src.Assets {
+		dst.Assets[id] = balance
+	}
+	return &dst
+}
 
-	// Generate item_branch randomly (500 ~ 3000)
-	branchAmount := rand.Intn(baseAmount) + 500
-	assets["item_branch"] = strconv.Itoa(branchAmount)
+// cloneAccountAssets copy account assets so stored objects are never modified in place
+func cloneAccountAssets(src *models.AccountAssets) *models.AccountAssets {
+	dst := *src
+	dst.Common = make(map[string]string, len(src.Common))
+	for id, balance := range src.Common {
+		dst.Common[id] = balance
+	}
+	return &dst
+}
 
-	// Generate item_horn randomly (500 ~ 3000)
-	hornAmount := rand.Intn(baseAmount) + 500
-	assets["item_horn"] = strconv.Itoa(hornAmount)
+// getOrCreateAccountAssetsTxn get or create account asset information within write transaction
+func getOrCreateAccountAssetsTxn(txn *memdb.Txn, accountID string, common map[string]string) (*models.AccountAssets, error) {
+	raw, err := txn.First("account_assets", "id", accountID)
+	if err != nil {
+		return nil, err
+	}
+	if raw != nil {
+		return raw.(*models.AccountAssets), nil
+	}
 
-	// Generate item_maple randomly (500 ~ 3000)
-	mapleAmount := rand.Intn(baseAmount) + 500
-	assets["item_maple"] = strconv.Itoa(mapleAmount)
+	accountAssets := newAccountAssets(accountID, common)
+	if err := txn.Insert("account_assets", accountAssets); err != nil {
+		return nil, err
+	}
+	return accountAssets, nil
+}
 
-	return assets
+// getSessionAssetsTxn get existing session asset information within transaction
+func getSessionAssetsTxn(txn *memdb.Txn, sessionID string) (*models.SessionAssets, error) {
+	raw, err := txn.First("session_assets", "id", sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	return raw.(*models.SessionAssets), nil
 }
 
-// GetOrCreateSessionAssets get or create session-specific asset information
-func GetOrCreateSessionAssets(sessionID string) (*models.SessionAssets, error) {
+// GetSessionAssets get existing session asset information (nil if the session is unknown)
+// Unlike GetOrCreateSessionAssets, unknown sessions are not loaded from the asset provider.
+func GetSessionAssets(sessionID string) (*models.SessionAssets, error) {
 	database, err := GetDB()
 	if err != nil {
 		return nil, err
 	}
 
-	// Start read transaction
 	txn := database.Txn(false)
 	defer txn.Abort()
 
-	// Query existing data
 	raw, err := txn.First("session_assets", "id", sessionID)
 	if err != nil {
 		return nil, err
 	}
+	if raw == nil {
+		return nil, nil
+	}
+	return raw.(*models.SessionAssets), nil
+}
+
+// ListSessions list stored sessions ordered by session ID, with the total number of sessions
+func ListSessions(offset, limit int) ([]*models.SessionAssets, int, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	it, err := txn.Get("session_assets", "id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sessions []*models.SessionAssets
+	total := 0
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		if total >= offset && (limit <= 0 || len(sessions) < limit) {
+			sessions = append(sessions, obj.(*models.SessionAssets))
+		}
+		total++
+	}
+	return sessions, total, nil
+}
+
+// GetOrCreateSessionAssets get or create session-specific asset information
+// Unknown sessions are loaded from the asset provider once admitted by the session policy
+func GetOrCreateSessionAssets(sessionID string) (*models.SessionAssets, error) {
+	return getOrCreateSessionAssets(sessionID, false)
+}
+
+// AdmitSessionAssets get or create a session whose player proved wallet ownership
+// SESSION_REQUIRE_KNOWN is skipped since the enrollment vouches for the session; the session cap still applies.
+func AdmitSessionAssets(sessionID string) (*models.SessionAssets, error) {
+	return getOrCreateSessionAssets(sessionID, true)
+}
+
+// getOrCreateSessionAssets get or create session assets, checking the session policy unless admitted
+func getOrCreateSessionAssets(sessionID string, admitted bool) (*models.SessionAssets, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	// Query existing data with read transaction
+	txn := database.Txn(false)
+	raw, err := txn.First("session_assets", "id", sessionID)
+	txn.Abort()
+	if err != nil {
+		return nil, err
+	}
 
 	if raw != nil {
 		// Return existing data if found
+		touchSession(sessionID, time.Now())
 		sessionAssets := raw.(*models.SessionAssets)
 		return sessionAssets, nil
 	}
 
-	// Create new session assets
-	sessionAssets := &models.SessionAssets{
-		SessionID: sessionID,
-		Assets:    generateRandomAssets(),
-		CreatedAt: time.Now().Format(time.RFC3339),
-		UpdatedAt: time.Now().Format(time.RFC3339),
+	// Reject sessions the policy does not admit before anything is loaded
+	if !admitted {
+		if err := admitSession(context.Background(), sessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Load player from the game outside of the write transaction
+	player, err := getAssetProvider().LoadPlayer(context.Background(), sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load player %s: %w", sessionID, err)
 	}
 
-	// Start write transaction
+	// Create new session assets with write transaction
 	txn = database.Txn(true)
-	err = txn.Insert("session_assets", sessionAssets)
+	defer txn.Abort()
+
+	// Another request may have created the session in the meantime
+	raw, err = txn.First("session_assets", "id", sessionID)
 	if err != nil {
-		txn.Abort()
+		return nil, err
+	}
+	if raw != nil {
+		touchSession(sessionID, time.Now())
+		return raw.(*models.SessionAssets), nil
+	}
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	// Evict least recently used sessions when the store is full
+	evicted, err := makeRoomTxn(txn, sessionPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionAssets := newSessionAssets(sessionID, player)
+	if err := txn.Insert("session_assets", sessionAssets); err != nil {
+		return nil, err
+	}
+
+	// Every character belongs to an account holding the common assets
+	if _, err := getOrCreateAccountAssetsTxn(txn, sessionAssets.AccountID, player.Common); err != nil {
 		return nil, err
 	}
 
+	for _, evictedID := range evicted {
+		forgetSessionLocked(evictedID)
+	}
+	touchSessionLocked(sessionID, time.Now())
 	txn.Commit()
+	recordEvictions(EvictionCapacity, evicted)
 	return sessionAssets, nil
 }
 
+// GetOrCreateAccountAssets get or create account-wide asset information
+func GetOrCreateAccountAssets(accountID string) (*models.AccountAssets, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	accountAssets, err := getOrCreateAccountAssetsTxn(txn, accountID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+	return accountAssets, nil
+}
+
+// ListAccountCharacters list characters (sessions) that belong to the account
+func ListAccountCharacters(accountID string) ([]*models.SessionAssets, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	it, err := txn.Get("session_assets", "account", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var characters []*models.SessionAssets
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		characters = append(characters, obj.(*models.SessionAssets))
+	}
+
+	// Oldest character first
+	sort.Slice(characters, func(i, j int) bool {
+		if characters[i].CreatedAt != characters[j].CreatedAt {
+			return characters[i].CreatedAt < characters[j].CreatedAt
+		}
+		return characters[i].SessionID < characters[j].SessionID
+	})
+	return characters, nil
+}
+
 // CheckAndDeductAssets validate and deduct asset balance
-func CheckAndDeductAssets(sessionID string, fromAssets []models.PairAsset) error {
-	// Get session asset information
-	sessionAssets, err := GetOrCreateSessionAssets(sessionID)
+// Account-scoped assets are deducted from the account's common balances; each deduction is recorded in the ledger with ref
+func CheckAndDeductAssets(sessionID string, fromAssets []models.PairAsset, ref LedgerRef) error {
+	database, err := GetDB()
 	if err != nil {
 		return err
 	}
 
+	// Make sure the session exists before taking the write lock
+	if _, err := GetOrCreateSessionAssets(sessionID); err != nil {
+		return err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	// Get session and account asset information
+	stored, err := getSessionAssetsTxn(txn, sessionID)
+	if err != nil {
+		return err
+	}
+	storedAccount, err := getOrCreateAccountAssetsTxn(txn, stored.AccountID, nil)
+	if err != nil {
+		return err
+	}
+	sessionAssets := cloneSessionAssets(stored)
+	accountAssets := cloneAccountAssets(storedAccount)
+
 	// Validate and deduct balance for each asset
+	c := catalog.Get()
 	for _, asset := range fromAssets {
-		currentBalance, exists := sessionAssets.Assets[asset.AssetID]
+		balances := sessionAssets.Assets
+		if c.IsAccountScoped(asset.AssetID) {
+			balances = accountAssets.Common
+		}
+
+		currentBalance, exists := balances[asset.AssetID]
 		if !exists {
-			return fmt.Errorf("asset %s not found in session", asset.AssetID)
+			return fmt.Errorf("%w: %s in session %s", ErrAssetNotFound, asset.AssetID, sessionID)
+		}
+
+		amount, err := ledgerAmount(asset)
+		if err != nil {
+			return err
 		}
 
 		// Convert string to integer
-		currentAmount, err := strconv.Atoi(currentBalance)
+		currentAmount, err := strconv.ParseUint(currentBalance, 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid balance format for asset %s", asset.AssetID)
+			return fmt.Errorf("%w for asset %s: %q", ErrCorruptBalance, asset.AssetID, currentBalance)
 		}
 
 		// Validate balance
-		if currentAmount < int(asset.Amount) {
-			return fmt.Errorf("insufficient balance for asset %s: required %d, available %d", asset.AssetID, asset.Amount, currentAmount)
+		if currentAmount < uint64(amount) {
+			return fmt.Errorf("%w for asset %s: required %d, available %d", ErrInsufficientBalance, asset.AssetID, amount, currentAmount)
 		}
 
 		// Deduct
-		newBalance := currentAmount - int(asset.Amount)
-		sessionAssets.Assets[asset.AssetID] = strconv.Itoa(newBalance)
-
+		balances[asset.AssetID] = strconv.FormatUint(currentAmount-uint64(amount), 10)
+		if err := insertLedgerTxn(txn, sessionID, sessionAssets.AccountID, asset.AssetID, -amount, balances[asset.AssetID], ref); err != nil {
+			return err
+		}
 	}
 
-	// Set update time
-	sessionAssets.UpdatedAt = time.Now().Format(time.RFC3339)
-
-	// Save to DB
-	txn := db.Txn(true)
-	err = txn.Insert("session_assets", sessionAssets)
-	if err != nil {
-		txn.Abort()
+	if err := saveAssetsTxn(txn, sessionAssets, accountAssets); err != nil {
 		return err
 	}
 
 	txn.Commit()
+	recordAmounts(metrics.AssetsDeducted, fromAssets)
 	return nil
 }
 
 // AddAssets increase assets
-func AddAssets(sessionID string, assets []models.PairAsset) error {
-	// Get session asset information
-	sessionAssets, err := GetOrCreateSessionAssets(sessionID)
+// Account-scoped assets are credited to the account's common balances; each credit is recorded in the ledger with ref
+func AddAssets(sessionID string, assets []models.PairAsset, ref LedgerRef) error {
+	database, err := GetDB()
 	if err != nil {
 		return err
 	}
 
-	// Increase balance for each asset
-	for _, asset := range assets {
-		currentBalance, exists := sessionAssets.Assets[asset.AssetID]
-		if !exists {
-			// Create new asset if it doesn't exist
-			sessionAssets.Assets[asset.AssetID] = strconv.FormatUint(uint64(asset.Amount), 10)
-		} else {
-			// Add to existing balance
-			currentAmount, err := strconv.ParseUint(currentBalance, 10, 64)
-			if err != nil {
-				return fmt.Errorf("invalid balance format for asset %s", asset.AssetID)
-			}
-
-			newBalance := currentAmount + uint64(asset.Amount)
-			sessionAssets.Assets[asset.AssetID] = strconv.FormatUint(newBalance, 10)
-		}
+	// Make sure the session exists before taking the write lock
+	if _, err := GetOrCreateSessionAssets(sessionID); err != nil {
+		return err
 	}
 
-	// Set update time
-	sessionAssets.UpdatedAt = time.Now().Format(time.RFC3339)
+	txn := database.Txn(true)
+	defer txn.Abort()
 
-	// Save to DB
-	txn := db.Txn(true)
-	err = txn.Insert("session_assets", sessionAssets)
-	if err != nil {
-		txn.Abort()
+	if err := addAssetsTxn(txn, sessionID, assets, ref); err != nil {
 		return err
 	}
 
 	txn.Commit()
+	recordAmounts(metrics.AssetsCredited, assets)
 	return nil
 }
 
-// UUIDMapping UUID 매핑 구조체
-type UUIDMapping struct {
-	UUID      string `json:"uuid"`
-	SessionID string `json:"session_id"`
-}
-
-// StoreUUIDMapping UUID와 SessionID 매핑 저장
-func StoreUUIDMapping(uuid, sessionID string) error {
-	mapping := &UUIDMapping{
-		UUID:      uuid,
-		SessionID: sessionID,
+// recordAmounts add committed asset amounts to a deducted or credited counter
+func recordAmounts(counter *prometheus.CounterVec, assets []models.PairAsset) {
+	for _, asset := range assets {
+		metrics.AddAmount(counter, asset.AssetID, uint64(asset.Amount))
 	}
+}
 
-	database, err := GetDB()
+// addAssetsTxn increase assets within write transaction
+func addAssetsTxn(txn *memdb.Txn, sessionID string, assets []models.PairAsset, ref LedgerRef) error {
+	// Get session and account asset information
+	stored, err := getSessionAssetsTxn(txn, sessionID)
 	if err != nil {
-		slog.Error("StoreUUIDMapping", "error", "Failed to get database", "err", err)
 		return err
 	}
-
-	txn := database.Txn(true)
-	err = txn.Insert("uuid_mapping", mapping)
+	storedAccount, err := getOrCreateAccountAssetsTxn(txn, stored.AccountID, nil)
 	if err != nil {
-		txn.Abort()
 		return err
 	}
+	sessionAssets := cloneSessionAssets(stored)
+	accountAssets := cloneAccountAssets(storedAccount)
 
-	txn.Commit()
-	slog.Info("StoreUUIDMapping", "uuid", uuid, "sessionID", sessionID, "action", "committed")
-	return nil
-}
+	// Increase balance for each asset
+	c := catalog.Get()
+	for _, asset := range assets {
+		balances := sessionAssets.Assets
+		if c.IsAccountScoped(asset.AssetID) {
+			balances = accountAssets.Common
+		}
 
-// GetSessionIDByUUID UUID로 SessionID 조회
-func GetSessionIDByUUID(uuid string) (string, error) {
-	database, err := GetDB()
-	if err != nil {
-		return "", err
-	}
+		amount, err := ledgerAmount(asset)
+		if err != nil {
+			return err
+		}
 
-	txn := database.Txn(false)
-	defer txn.Abort()
+		currentBalance, exists := balances[asset.AssetID]
+		if !exists {
+			// Create new asset if it doesn't exist
+			balances[asset.AssetID] = strconv.FormatUint(uint64(amount), 10)
+		} else {
+			// Add to existing balance
+			currentAmount, err := strconv.ParseUint(currentBalance, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w for asset %s: %q", ErrCorruptBalance, asset.AssetID, currentBalance)
+			}
+			if currentAmount > math.MaxUint64-uint64(amount) {
+				return fmt.Errorf("%w for asset %s: balance %d plus %d overflows", ErrAmountOutOfRange, asset.AssetID, currentAmount, amount)
+			}
 
-	raw, err := txn.First("uuid_mapping", "id", uuid)
-	if err != nil {
-		return "", err
+			balances[asset.AssetID] = strconv.FormatUint(currentAmount+uint64(amount), 10)
+		}
+		if err := insertLedgerTxn(txn, sessionID, sessionAssets.AccountID, asset.AssetID, amount, balances[asset.AssetID], ref); err != nil {
+			return err
+		}
 	}
 
-	if raw == nil {
-		slog.Warn("GetSessionIDByUUID", "warning", "UUID not found", "uuid", uuid)
-		return "", fmt.Errorf("uuid mapping not found: %s", uuid)
+	return saveAssetsTxn(txn, sessionAssets, accountAssets)
+}
+
+// ledgerAmount amount of an asset as a ledger delta
+// Amounts above math.MaxInt64 cannot be recorded and are rejected instead of wrapping.
+func ledgerAmount(asset models.PairAsset) (int64, error) {
+	if uint64(asset.Amount) > math.MaxInt64 {
+		return 0, fmt.Errorf("%w for asset %s: %d", ErrAmountOutOfRange, asset.AssetID, asset.Amount)
 	}
+	return int64(asset.Amount), nil
+}
+
+// saveAssetsTxn store updated session and account assets within write transaction
+func saveAssetsTxn(txn *memdb.Txn, sessionAssets *models.SessionAssets, accountAssets *models.AccountAssets) error {
+	// Set update time
+	now := time.Now().Format(time.RFC3339)
+	sessionAssets.UpdatedAt = now
+	accountAssets.UpdatedAt = now
 
-	mapping := raw.(*UUIDMapping)
-	sessionID := mapping.SessionID
-	slog.Info("GetSessionIDByUUID", "uuid", uuid, "sessionID", sessionID)
-	return sessionID, nil
+	// Save to DB
+	if err := txn.Insert("session_assets", sessionAssets); err != nil {
+		return err
+	}
+	return txn.Insert("account_assets", accountAssets)
 }
@@ -1,8 +1,10 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/provider"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,19 +21,24 @@ func TestStoreAndGetUUIDMapping(t *testing.T) {
 	testUUID := "test-uuid-123"
 	testSessionID := "session-test-456"
 
-	// UUID 매핑 저장 테스트
-	err = StoreUUIDMapping(testUUID, testSessionID)
-	assert.NoError(t, err, "Failed to store UUID mapping")
+	// 주문 저장 테스트
+	err = StoreOrder(&UUIDMapping{UUID: testUUID, SessionID: testSessionID})
+	assert.NoError(t, err, "Failed to store order")
 
-	// UUID로 SessionID 조회 테스트
-	retrievedSessionID, err := GetSessionIDByUUID(testUUID)
-	assert.NoError(t, err, "Failed to get session ID by UUID")
-	assert.Equal(t, testSessionID, retrievedSessionID, "Retrieved session ID should match stored session ID")
+	// UUID로 주문 조회 테스트
+	order, err := GetOrder(testUUID)
+	assert.NoError(t, err, "Failed to get order by UUID")
+	require.NotNil(t, order, "Stored order should be found")
+	assert.Equal(t, testSessionID, order.SessionID, "Retrieved session ID should match stored session ID")
+	assert.Equal(t, OrderStatusValidated, order.Status, "Stored order should start validated")
+
+	// 같은 UUID는 다시 저장할 수 없음
+	assert.ErrorIs(t, StoreOrder(&UUIDMapping{UUID: testUUID, SessionID: testSessionID}), ErrOrderExists)
 
 	// 존재하지 않는 UUID 조회 테스트
-	_, err = GetSessionIDByUUID("non-existent-uuid")
-	assert.Error(t, err, "Should return error for non-existent UUID")
-	assert.Contains(t, err.Error(), "uuid mapping not found", "Error message should indicate mapping not found")
+	order, err = GetOrder("non-existent-uuid")
+	assert.NoError(t, err, "Missing orders are not an error")
+	assert.Nil(t, order, "Should return no order for non-existent UUID")
 }
 
 func TestGetOrCreateSessionAssets(t *testing.T) {
@@ -87,7 +94,7 @@ func TestCheckAndDeductAssets(t *testing.T) {
 		{Type: "asset", AssetID: "asset_gold", Amount: 500},
 	}
 
-	err = CheckAndDeductAssets(testSessionID, deductAssets)
+	err = CheckAndDeductAssets(testSessionID, deductAssets, LedgerRef{})
 	assert.NoError(t, err, "Failed to deduct assets")
 
 	// 차감 후 자산 잔액 확인
@@ -99,6 +106,54 @@ func TestCheckAndDeductAssets(t *testing.T) {
 	assert.NotEqual(t, initialGoldBalance, updatedSessionAssets.Assets["asset_gold"], "Gold balance should be deducted")
 }
 
+func TestCheckAndDeductAssetsErrors(t *testing.T) {
+	require.NoError(t, InitDB(), "Failed to initialize test database")
+	defer CloseDB()
+
+	testSessionID := "test-session-deduct-errors"
+	sessionAssets, err := GetOrCreateSessionAssets(testSessionID)
+	require.NoError(t, err, "Failed to create session assets")
+
+	// 보유하지 않은 자산
+	err = CheckAndDeductAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_unknown", Amount: 1}}, LedgerRef{})
+	assert.ErrorIs(t, err, ErrAssetNotFound)
+
+	// 잔액 부족
+	err = CheckAndDeductAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1 << 40}}, LedgerRef{})
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+
+	// int64 범위를 넘는 수량은 음수로 감기지 않고 거부
+	before, err := GetSessionAssets(testSessionID)
+	require.NoError(t, err)
+	err = CheckAndDeductAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1<<63 + 5}}, LedgerRef{})
+	assert.ErrorIs(t, err, ErrAmountOutOfRange)
+	err = AddAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1<<63 + 5}}, LedgerRef{})
+	assert.ErrorIs(t, err, ErrAmountOutOfRange)
+	after, err := GetSessionAssets(testSessionID)
+	require.NoError(t, err)
+	assert.Equal(t, before.Assets["asset_money"], after.Assets["asset_money"])
+
+	// uint64 잔액 넘침
+	for range 2 {
+		err = AddAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_overflow", Amount: 1<<63 - 1}}, LedgerRef{})
+		require.NoError(t, err)
+	}
+	err = AddAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_overflow", Amount: 2}}, LedgerRef{})
+	assert.ErrorIs(t, err, ErrAmountOutOfRange)
+
+	// 손상된 잔액
+	corrupted := cloneSessionAssets(sessionAssets)
+	corrupted.Assets["asset_money"] = "not-a-number"
+	txn := db.Txn(true)
+	require.NoError(t, txn.Insert("session_assets", corrupted))
+	txn.Commit()
+
+	err = CheckAndDeductAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1}}, LedgerRef{})
+	assert.ErrorIs(t, err, ErrCorruptBalance)
+	err = AddAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1}}, LedgerRef{})
+	assert.ErrorIs(t, err, ErrCorruptBalance)
+}
+
 func TestAddAssets(t *testing.T) {
 	// DB 초기화
 	err := InitDB()
@@ -122,7 +177,7 @@ func TestAddAssets(t *testing.T) {
 		{AssetID: "new_asset", Amount: 200}, // 새로운 자산
 	}
 
-	err = AddAssets(testSessionID, addAssets)
+	err = AddAssets(testSessionID, addAssets, LedgerRef{})
 	assert.NoError(t, err, "Failed to add assets")
 
 	// 증가 후 자산 잔액 확인
@@ -148,18 +203,19 @@ func TestUUIDMappingWorkflow(t *testing.T) {
 	testUUID := "workflow-test-uuid"
 	testSessionID := "workflow-session"
 
-	// 1. UUID 매핑 저장 (validate 단계)
-	err = StoreUUIDMapping(testUUID, testSessionID)
-	assert.NoError(t, err, "Failed to store UUID mapping in validate step")
+	// 1. 주문 저장 (validate 단계)
+	err = StoreOrder(&UUIDMapping{UUID: testUUID, SessionID: testSessionID})
+	assert.NoError(t, err, "Failed to store order in validate step")
 
 	// 2. 세션 자산 생성
 	_, err = GetOrCreateSessionAssets(testSessionID)
 	require.NoError(t, err, "Failed to create session assets")
 
-	// 3. UUID로 SessionID 조회 (result 단계)
-	retrievedSessionID, err := GetSessionIDByUUID(testUUID)
-	assert.NoError(t, err, "Failed to get session ID by UUID in result step")
-	assert.Equal(t, testSessionID, retrievedSessionID, "Retrieved session ID should match")
+	// 3. UUID로 주문 조회 (result 단계)
+	order, err := GetOrder(testUUID)
+	assert.NoError(t, err, "Failed to get order by UUID in result step")
+	require.NotNil(t, order, "Stored order should be found in result step")
+	assert.Equal(t, testSessionID, order.SessionID, "Retrieved session ID should match")
 
 	// 4. 자산 증가 처리 (result 단계)
 	addAssets := []models.PairAsset{
@@ -167,11 +223,11 @@ func TestUUIDMappingWorkflow(t *testing.T) {
 		{AssetID: "asset_gold", Amount: 500},
 	}
 
-	err = AddAssets(retrievedSessionID, addAssets)
+	err = AddAssets(order.SessionID, addAssets, LedgerRef{})
 	assert.NoError(t, err, "Failed to add assets in result step")
 
 	// 5. 최종 자산 확인
-	finalSessionAssets, err := GetOrCreateSessionAssets(retrievedSessionID)
+	finalSessionAssets, err := GetOrCreateSessionAssets(order.SessionID)
 	require.NoError(t, err, "Failed to get final session assets")
 
 	// 자산이 증가되었는지 확인
@@ -201,7 +257,7 @@ func TestConcurrentAccess(t *testing.T) {
 			addAssets := []models.PairAsset{
 				{AssetID: "asset_money", Amount: uint(id * 100)},
 			}
-			err = AddAssets(sessionID, addAssets)
+			err = AddAssets(sessionID, addAssets, LedgerRef{})
 			assert.NoError(t, err)
 
 			done <- true
@@ -213,3 +269,98 @@ func TestConcurrentAccess(t *testing.T) {
 		<-done
 	}
 }
+
+func TestAccountScopedAssets(t *testing.T) {
+	// DB 초기화
+	err := InitDB()
+	require.NoError(t, err, "Failed to initialize test database")
+	defer CloseDB()
+
+	testSessionID := "test-session-account"
+
+	// 세션 생성 시 계정 공용 자산도 함께 생성되는지 확인
+	sessionAssets, err := GetOrCreateSessionAssets(testSessionID)
+	require.NoError(t, err, "Failed to create session assets")
+	assert.Equal(t, testSessionID, sessionAssets.AccountID, "Account ID should default to session ID")
+	_, exists := sessionAssets.Assets["asset_diamond"]
+	assert.False(t, exists, "Account-scoped asset should not be in character inventory")
+
+	accountAssets, err := GetOrCreateAccountAssets(sessionAssets.AccountID)
+	require.NoError(t, err, "Failed to get account assets")
+	_, exists = accountAssets.Common["asset_diamond"]
+	assert.True(t, exists, "Account-scoped asset should be in common balances")
+
+	// 계정 자산 증가 후 공용 잔액에 반영되는지 확인
+	err = AddAssets(testSessionID, []models.PairAsset{{AssetID: "asset_diamond", Amount: 10}}, LedgerRef{})
+	require.NoError(t, err, "Failed to add account assets")
+
+	updatedAccount, err := GetOrCreateAccountAssets(sessionAssets.AccountID)
+	require.NoError(t, err)
+	assert.NotEqual(t, accountAssets.Common["asset_diamond"], updatedAccount.Common["asset_diamond"], "Common balance should be increased")
+
+	// 계정 자산 차감
+	err = CheckAndDeductAssets(testSessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_diamond", Amount: 10}}, LedgerRef{})
+	assert.NoError(t, err, "Failed to deduct account assets")
+
+	finalAccount, err := GetOrCreateAccountAssets(sessionAssets.AccountID)
+	require.NoError(t, err)
+	assert.Equal(t, accountAssets.Common["asset_diamond"], finalAccount.Common["asset_diamond"], "Common balance should be restored")
+
+	// 계정에 속한 캐릭터 목록 조회
+	characters, err := ListAccountCharacters(sessionAssets.AccountID)
+	require.NoError(t, err)
+	require.Len(t, characters, 1)
+	assert.Equal(t, testSessionID, characters[0].SessionID)
+}
+
+// staticProvider 테스트용 자산 제공자
+type staticProvider struct {
+	players map[string]*provider.Player
+}
+
+func (p *staticProvider) LoadPlayer(ctx context.Context, sessionID string) (*provider.Player, error) {
+	player, ok := p.players[sessionID]
+	if !ok {
+		return nil, provider.ErrPlayerNotFound
+	}
+	return player, nil
+}
+
+func TestAssetProvider(t *testing.T) {
+	// DB 초기화
+	err := InitDB()
+	require.NoError(t, err, "Failed to initialize test database")
+	defer CloseDB()
+
+	// 테스트용 제공자 설정 후 기본 제공자로 복원
+	SetAssetProvider(&staticProvider{players: map[string]*provider.Player{
+		"provider-session": {
+			AccountID:     "provider-account",
+			Name:          "Hero",
+			WalletAddress: "0xB777C937fa1afC99606aFa85c5b83cFe7f82BabD",
+			Server:        "asia-1",
+			Assets:        map[string]string{"asset_money": "1500"},
+			Common:        map[string]string{"asset_diamond": "30"},
+		},
+	}})
+	defer SetAssetProvider(provider.NewDemoProvider())
+
+	// 제공자가 반환한 프로필과 잔액이 저장되는지 확인
+	sessionAssets, err := GetOrCreateSessionAssets("provider-session")
+	require.NoError(t, err)
+	assert.Equal(t, "provider-account", sessionAssets.AccountID)
+	assert.Equal(t, "Hero", sessionAssets.Name)
+	assert.Equal(t, "asia-1", sessionAssets.Server)
+	assert.Equal(t, map[string]string{"asset_money": "1500"}, sessionAssets.Assets)
+
+	accountAssets, err := GetOrCreateAccountAssets("provider-account")
+	require.NoError(t, err)
+	assert.Equal(t, "30", accountAssets.Common["asset_diamond"])
+
+	// 제공자가 모르는 세션은 생성되지 않아야 함
+	_, err = GetOrCreateSessionAssets("unknown-session")
+	assert.ErrorIs(t, err, provider.ErrPlayerNotFound)
+
+	err = AddAssets("unknown-session", []models.PairAsset{{AssetID: "asset_money", Amount: 1}}, LedgerRef{})
+	assert.ErrorIs(t, err, provider.ErrPlayerNotFound)
+}
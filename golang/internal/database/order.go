@@ -0,0 +1,497 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"sample-game-backend/internal/metrics"
+	"sample-game-backend/internal/models"
+
+	"github.com/hashicorp/go-memdb"
+)
+
+// Order statuses
+const (
+	// OrderStatusValidated signed at validate, waiting for the result webhook
+	OrderStatusValidated = "validated"
+	// OrderStatusRejected rejected at validate after the order was stored
+	OrderStatusRejected = "rejected"
+	// OrderStatusSettled result webhook reported a successful transaction
+	OrderStatusSettled = "settled"
+	// OrderStatusFailed result webhook reported a failed transaction
+	OrderStatusFailed = "failed"
+	// OrderStatusExpired no result webhook arrived before the deadline
+	OrderStatusExpired = "expired"
+)
+
+// Order errors
+var (
+	ErrOrderExists      = errors.New("order already exists")
+	ErrOrderNotFound    = errors.New("order not found")
+	ErrOrderStatusStale = errors.New("order is not in the expected status")
+)
+
+// OrderEvent status change recorded in the order's history
+type OrderEvent struct {
+	Status string    `json:"status"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// UUIDMapping UUID 매핑 구조체
+// Orders stored at validate also track their status and the assets deducted for them
+type UUIDMapping struct {
+	UUID       string `json:"uuid"`
+	SessionID  string `json:"session_id"`
+	ProjectID  string `json:"project_id,omitempty"`
+	IntentType string `json:"intent_type,omitempty"`
+	Status     string `json:"status,omitempty"`
+	// UserAddress wallet the intent was signed for at validate
+	UserAddress string `json:"user_address,omitempty"`
+	// Intent intent signed at validate
+	Intent *models.ExchangeIntent `json:"intent,omitempty"`
+	// Deducted in-game assets deducted by an assemble validate
+	Deducted []models.PairAsset `json:"deducted,omitempty"`
+	// Refunded deducted assets were returned to the session
+	Refunded bool `json:"refunded,omitempty"`
+	// TxHash transaction hash reported by the order's result
+	TxHash string `json:"tx_hash,omitempty"`
+	// Flags anomalies recorded on the order (e.g. a result whose intent differs from Intent)
+	Flags     []string     `json:"flags,omitempty"`
+	History   []OrderEvent `json:"history,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// StoreOrder store a validated order (fails if the UUID was already used)
+func StoreOrder(order *UUIDMapping) error {
+	database, err := GetDB()
+	if err != nil {
+		slog.Error("StoreOrder", "error", "Failed to get database", "err", err)
+		return err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("uuid_mapping", "id", order.UUID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: %s", ErrOrderExists, order.UUID)
+	}
+	// Purged orders leave a tombstone so their UUID cannot be validated again
+	purged, err := txn.First("order_tombstone", "id", order.UUID)
+	if err != nil {
+		return err
+	}
+	if purged != nil {
+		return fmt.Errorf("%w: %s was purged", ErrOrderExists, order.UUID)
+	}
+
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = time.Now()
+	}
+	if order.Status == "" {
+		order.Status = OrderStatusValidated
+	}
+	order.UpdatedAt = order.CreatedAt
+	order.History = append(order.History, OrderEvent{Status: order.Status, At: order.CreatedAt})
+	if err := txn.Insert("uuid_mapping", order); err != nil {
+		return err
+	}
+
+	txn.Commit()
+	slog.Info("StoreOrder", "uuid", order.UUID, "sessionID", order.SessionID, "intentType", order.IntentType, "action", "committed")
+	return nil
+}
+
+// GetOrder get order by UUID (nil if the UUID is unknown)
+func GetOrder(uuid string) (*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	raw, err := txn.First("uuid_mapping", "id", uuid)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	return raw.(*UUIDMapping), nil
+}
+
+// GetOrderByTxHash get order by the transaction hash of its result (nil if no order has the hash)
+func GetOrderByTxHash(txHash string) (*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	raw, err := txn.First("uuid_mapping", "tx_hash", txHash)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	return raw.(*UUIDMapping), nil
+}
+
+// ListOrdersBySession list orders of the session, oldest first
+func ListOrdersBySession(sessionID string) ([]*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	it, err := txn.Get("uuid_mapping", "session", sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []*UUIDMapping
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		orders = append(orders, obj.(*UUIDMapping))
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	return orders, nil
+}
+
+// SetOrderTxHash record the transaction hash reported for the order
+func SetOrderTxHash(uuid, txHash string) error {
+	database, err := GetDB()
+	if err != nil {
+		return err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	raw, err := txn.First("uuid_mapping", "id", uuid)
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		return fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+
+	stored := raw.(*UUIDMapping)
+	if stored.TxHash == txHash {
+		return nil
+	}
+	updated := *stored
+	updated.TxHash = txHash
+	updated.UpdatedAt = time.Now()
+	if err := txn.Insert("uuid_mapping", &updated); err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// UpdateOrderStatus change order status if it is currently in one of the given statuses
+func UpdateOrderStatus(uuid, status, reason string, from ...string) (*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	updated, err := transitionOrderTxn(txn, uuid, status, reason, from...)
+	if err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+	slog.Info("UpdateOrderStatus", "uuid", uuid, "status", status, "reason", reason, "action", "committed")
+	return updated, nil
+}
+
+// FlagOrder record an anomaly on the order without changing its status
+func FlagOrder(uuid, flag, reason string) (*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	raw, err := txn.First("uuid_mapping", "id", uuid)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+
+	stored := raw.(*UUIDMapping)
+	now := time.Now()
+	updated := *stored
+	updated.Flags = append(append([]string(nil), stored.Flags...), flag)
+	updated.UpdatedAt = now
+	updated.History = append(append([]OrderEvent(nil), stored.History...), OrderEvent{Status: stored.Status, Reason: flag + ": " + reason, At: now})
+	if err := txn.Insert("uuid_mapping", &updated); err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+	slog.Warn("FlagOrder", "audit", flag, "uuid", uuid, "sessionID", updated.SessionID, "reason", reason)
+	return &updated, nil
+}
+
+// NoteOrder append a note (e.g. an operator action) to the order's history without changing its status
+func NoteOrder(uuid, note string) (*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	raw, err := txn.First("uuid_mapping", "id", uuid)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+
+	stored := raw.(*UUIDMapping)
+	now := time.Now()
+	updated := *stored
+	updated.UpdatedAt = now
+	updated.History = append(append([]OrderEvent(nil), stored.History...), OrderEvent{Status: stored.Status, Reason: note, At: now})
+	if err := txn.Insert("uuid_mapping", &updated); err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+	return &updated, nil
+}
+
+// CloseOrder move an order in one of the from statuses to a final status, returning its deducted assets
+// when refund is set. Assets are refunded at most once per order.
+func CloseOrder(uuid, status, reason string, refund bool, from ...string) (*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := GetOrder(uuid)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+	refund = refund && len(order.Deducted) > 0 && !order.Refunded
+
+	// Make sure the session exists before taking the write lock
+	if refund {
+		if _, err := GetOrCreateSessionAssets(order.SessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	updated, err := transitionOrderTxn(txn, uuid, status, reason, from...)
+	if err != nil {
+		return nil, err
+	}
+	// Checked again under the write lock so concurrent closes cannot both refund
+	refunded := refund && !updated.Refunded
+	if refunded {
+		if updated, err = refundOrderTxn(txn, updated, reason); err != nil {
+			return nil, err
+		}
+	}
+
+	txn.Commit()
+	if refunded {
+		recordAmounts(metrics.AssetsCredited, updated.Deducted)
+	}
+	slog.Info("CloseOrder", "uuid", uuid, "sessionID", updated.SessionID, "status", status, "refunded", updated.Refunded, "action", "committed")
+	return updated, nil
+}
+
+// RefundFailedOrder return the deducted assets of a failed order that was not refunded yet
+// The order stays failed and the refund is appended to its history.
+func RefundFailedOrder(uuid, reason string) (*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := GetOrder(uuid)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+	if _, err := GetOrCreateSessionAssets(order.SessionID); err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	updated, err := transitionOrderTxn(txn, uuid, OrderStatusFailed, reason, OrderStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+	// Checked under the write lock so a refund never runs twice
+	if updated.Refunded || len(updated.Deducted) == 0 {
+		return nil, fmt.Errorf("%w: %s has nothing left to refund", ErrOrderStatusStale, uuid)
+	}
+	if updated, err = refundOrderTxn(txn, updated, reason); err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+	recordAmounts(metrics.AssetsCredited, updated.Deducted)
+	slog.Info("RefundFailedOrder", "uuid", uuid, "sessionID", updated.SessionID, "refunded", updated.Deducted, "action", "committed")
+	return updated, nil
+}
+
+// refundOrderTxn credit the deducted assets of an order back to its session and mark it refunded
+func refundOrderTxn(txn *memdb.Txn, order *UUIDMapping, reason string) (*UUIDMapping, error) {
+	if err := addAssetsTxn(txn, order.SessionID, order.Deducted, LedgerRef{Source: LedgerSourceRefund, Reference: order.UUID, Reason: reason}); err != nil {
+		return nil, err
+	}
+	refunded := *order
+	refunded.Refunded = true
+	if err := txn.Insert("uuid_mapping", &refunded); err != nil {
+		return nil, err
+	}
+	return &refunded, nil
+}
+
+// transitionOrderTxn change order status and append the change to its history
+// Any current status is accepted when from is empty
+func transitionOrderTxn(txn *memdb.Txn, uuid, status, reason string, from ...string) (*UUIDMapping, error) {
+	raw, err := txn.First("uuid_mapping", "id", uuid)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+
+	stored := raw.(*UUIDMapping)
+	if len(from) > 0 {
+		allowed := false
+		for _, s := range from {
+			if stored.Status == s {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: %s is %s", ErrOrderStatusStale, uuid, stored.Status)
+		}
+	}
+
+	now := time.Now()
+	updated := *stored
+	updated.Status = status
+	updated.UpdatedAt = now
+	updated.History = append(append([]OrderEvent(nil), stored.History...), OrderEvent{Status: status, Reason: reason, At: now})
+	if err := txn.Insert("uuid_mapping", &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ListOrdersByStatus list orders in the given status
+func ListOrdersByStatus(status string) ([]*UUIDMapping, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	it, err := txn.Get("uuid_mapping", "status", status)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []*UUIDMapping
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		orders = append(orders, obj.(*UUIDMapping))
+	}
+	return orders, nil
+}
+
+// CountPendingOrders count validated orders waiting for a result by intent type
+func CountPendingOrders() (map[string]int, error) {
+	orders, err := ListOrdersByStatus(OrderStatusValidated)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, order := range orders {
+		counts[order.IntentType]++
+	}
+	return counts, nil
+}
+
+// orderTombstone UUID of a purged order
+type orderTombstone struct {
+	UUID     string
+	PurgedAt time.Time
+}
+
+// DeleteOrder remove a finished order and its reservation, leaving a tombstone of its UUID
+// The order's rule usage is kept until PurgeOrderUsage drops it after the rules stop reading it.
+func DeleteOrder(uuid string) error {
+	database, err := GetDB()
+	if err != nil {
+		return err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	for _, table := range []string{"uuid_mapping", "reservation"} {
+		if _, err := txn.DeleteAll(table, "id", uuid); err != nil {
+			return err
+		}
+	}
+	if err := txn.Insert("order_tombstone", &orderTombstone{UUID: uuid, PurgedAt: time.Now()}); err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
@@ -0,0 +1,244 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"sample-game-backend/internal/metrics"
+	"sample-game-backend/internal/models"
+
+	"github.com/hashicorp/go-memdb"
+)
+
+// Reservation statuses
+const (
+	// ReservationPending credit recorded at validate, waiting for the result webhook
+	ReservationPending = "pending"
+	// ReservationConfirmed credit applied after a successful result
+	ReservationConfirmed = "confirmed"
+	// ReservationReleased credit dropped after a failed result
+	ReservationReleased = "released"
+	// ReservationExpired credit dropped because no result arrived in time
+	ReservationExpired = "expired"
+)
+
+// Reservation errors
+var (
+	ErrReservationExists     = errors.New("reservation already exists")
+	ErrReservationNotPending = errors.New("reservation is not pending")
+)
+
+// Reservation pending credit of a disassemble order
+type Reservation struct {
+	UUID      string             `json:"uuid"`
+	ProjectID string             `json:"project_id"`
+	SessionID string             `json:"session_id"`
+	Assets    []models.PairAsset `json:"assets"`
+	Status    string             `json:"status"`
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// CreateReservation record pending credit of a validated disassemble order
+func CreateReservation(reservation *Reservation) error {
+	database, err := GetDB()
+	if err != nil {
+		return err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("reservation", "id", reservation.UUID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: %s", ErrReservationExists, reservation.UUID)
+	}
+
+	reservation.Status = ReservationPending
+	reservation.UpdatedAt = reservation.CreatedAt
+	if err := txn.Insert("reservation", reservation); err != nil {
+		return err
+	}
+
+	txn.Commit()
+	slog.Info("CreateReservation", "uuid", reservation.UUID, "sessionID", reservation.SessionID, "assets", reservation.Assets, "action", "committed")
+	return nil
+}
+
+// GetReservation get reservation of order (nil if the order has no reservation)
+func GetReservation(uuid string) (*Reservation, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	raw, err := txn.First("reservation", "id", uuid)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	return raw.(*Reservation), nil
+}
+
+// ConfirmReservation credit assets to the session and mark reservation confirmed in one transaction
+// Expired reservations can still be confirmed because the tokens were already burned on-chain;
+// their usage, dropped at expiry, is recorded again so late credits still count toward issuance limits
+func ConfirmReservation(uuid string, assets []models.PairAsset) (*Reservation, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := GetReservation(uuid)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, fmt.Errorf("reservation not found: %s", uuid)
+	}
+
+	// Make sure the session exists before taking the write lock
+	if _, err := GetOrCreateSessionAssets(reservation.SessionID); err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	updated, err := transitionReservationTxn(txn, uuid, ReservationConfirmed, ReservationPending, ReservationExpired)
+	if err != nil {
+		return nil, err
+	}
+	if err := addAssetsTxn(txn, updated.SessionID, assets, LedgerRef{Source: LedgerSourceResult, Reference: uuid}); err != nil {
+		return nil, err
+	}
+	if reservation.Status == ReservationExpired {
+		if err := restoreUsageTxn(txn, updated, assets); err != nil {
+			return nil, err
+		}
+	}
+
+	txn.Commit()
+	recordAmounts(metrics.AssetsCredited, assets)
+	slog.Info("ConfirmReservation", "uuid", uuid, "sessionID", updated.SessionID, "assets", assets, "action", "committed")
+	return updated, nil
+}
+
+// ReleaseReservation drop pending credit with released or expired status
+func ReleaseReservation(uuid, status string) (*Reservation, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	updated, err := transitionReservationTxn(txn, uuid, status, ReservationPending)
+	if err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+	slog.Info("ReleaseReservation", "uuid", uuid, "sessionID", updated.SessionID, "status", status, "action", "committed")
+	return updated, nil
+}
+
+// transitionReservationTxn change reservation status if it is currently in one of the given statuses
+func transitionReservationTxn(txn *memdb.Txn, uuid, status string, from ...string) (*Reservation, error) {
+	raw, err := txn.First("reservation", "id", uuid)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("reservation not found: %s", uuid)
+	}
+
+	stored := raw.(*Reservation)
+	allowed := false
+	for _, s := range from {
+		if stored.Status == s {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: %s is %s", ErrReservationNotPending, uuid, stored.Status)
+	}
+
+	updated := *stored
+	updated.Status = status
+	updated.UpdatedAt = time.Now()
+	if err := txn.Insert("reservation", &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ListPendingReservations list pending reservations of a session
+func ListPendingReservations(sessionID string) ([]*Reservation, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	it, err := txn.Get("reservation", "session", sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reservations []*Reservation
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		reservation := obj.(*Reservation)
+		if reservation.Status == ReservationPending {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations, nil
+}
+
+// ListReservationsByStatus list reservations of every session in the given status
+func ListReservationsByStatus(status string) ([]*Reservation, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	it, err := txn.Get("reservation", "status", status)
+	if err != nil {
+		return nil, err
+	}
+
+	var reservations []*Reservation
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		reservations = append(reservations, obj.(*Reservation))
+	}
+	return reservations, nil
+}
+
+// CountPendingReservations count credits reserved until their result webhook
+func CountPendingReservations() (int, error) {
+	reservations, err := ListReservationsByStatus(ReservationPending)
+	if err != nil {
+		return 0, err
+	}
+	return len(reservations), nil
+}
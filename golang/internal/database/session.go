@@ -0,0 +1,254 @@
+package database
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"sample-game-backend/internal/metrics"
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/provider"
+
+	"github.com/hashicorp/go-memdb"
+)
+
+// Session admission errors
+var (
+	// ErrSessionLimit every stored session is in use and none can be evicted for a new one
+	ErrSessionLimit = errors.New("session limit reached")
+	// ErrSessionNotRecognized neither the asset provider nor the enrollment registry knows the session
+	ErrSessionNotRecognized = fmt.Errorf("%w: session not recognized", provider.ErrPlayerNotFound)
+)
+
+// Session eviction reasons (also the "reason" label of evicted session metrics)
+const (
+	EvictionIdle     = "idle"
+	EvictionCapacity = "capacity"
+)
+
+// SessionPolicy admission control of sessions created from X-Dapp-SessionID
+type SessionPolicy struct {
+	// RequireKnown create only sessions that are enrolled or recognized by the asset provider
+	RequireKnown bool
+	// MaxSessions stored sessions; the least recently used idle session is evicted for a new one (0 is unlimited)
+	MaxSessions int
+	// IdleTTL time an unused session is kept (0 keeps sessions forever)
+	IdleTTL time.Duration
+}
+
+// sessionUse last use of a stored session
+type sessionUse struct {
+	sessionID string
+	lastUsed  time.Time
+}
+
+var (
+	sessionPolicy SessionPolicy
+	// sessionLRU stored sessions, most recently used first
+	sessionLRU   = list.New()
+	sessionIndex = make(map[string]*list.Element)
+	sessionMu    sync.Mutex
+)
+
+// SetSessionPolicy set the admission control of new sessions
+func SetSessionPolicy(policy SessionPolicy) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	sessionPolicy = policy
+}
+
+// getSessionPolicy return configured session policy
+func getSessionPolicy() SessionPolicy {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return sessionPolicy
+}
+
+// touchSession mark the session as used now
+func touchSession(sessionID string, now time.Time) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	touchSessionLocked(sessionID, now)
+}
+
+func touchSessionLocked(sessionID string, now time.Time) {
+	if elem, ok := sessionIndex[sessionID]; ok {
+		elem.Value.(*sessionUse).lastUsed = now
+		sessionLRU.MoveToFront(elem)
+		return
+	}
+	sessionIndex[sessionID] = sessionLRU.PushFront(&sessionUse{sessionID: sessionID, lastUsed: now})
+}
+
+func forgetSessionLocked(sessionID string) {
+	if elem, ok := sessionIndex[sessionID]; ok {
+		sessionLRU.Remove(elem)
+		delete(sessionIndex, sessionID)
+	}
+}
+
+// admitSession check a session that is not stored yet may be created
+func admitSession(ctx context.Context, sessionID string) error {
+	if !getSessionPolicy().RequireKnown {
+		return nil
+	}
+
+	enrollment, err := GetWalletEnrollment(sessionID)
+	if err != nil {
+		return err
+	}
+	if enrollment != nil {
+		return nil
+	}
+
+	if recognizer, ok := getAssetProvider().(provider.SessionRecognizer); ok {
+		known, err := recognizer.RecognizesSession(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("recognize session %s: %w", sessionID, err)
+		}
+		if known {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrSessionNotRecognized, sessionID)
+}
+
+// makeRoomTxn evict least recently used idle sessions until a new session fits under MaxSessions
+// Must be called with sessionMu held; evicted sessions are dropped from the LRU by the caller after commit.
+func makeRoomTxn(txn *memdb.Txn, policy SessionPolicy) ([]string, error) {
+	if policy.MaxSessions <= 0 {
+		return nil, nil
+	}
+
+	var evicted []string
+	excess := sessionLRU.Len() - policy.MaxSessions + 1
+	for elem := sessionLRU.Back(); elem != nil && len(evicted) < excess; elem = elem.Prev() {
+		sessionID := elem.Value.(*sessionUse).sessionID
+		removed, err := evictSessionTxn(txn, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if removed {
+			evicted = append(evicted, sessionID)
+		}
+	}
+	if len(evicted) < excess {
+		return nil, fmt.Errorf("%w: %d sessions", ErrSessionLimit, sessionLRU.Len())
+	}
+	return evicted, nil
+}
+
+// evictSessionTxn remove a session that has no validated order, pending credit or balance change
+// Account balances are removed with the account's last character. Orders and wallet enrollments
+// are kept, so an evicted session is reloaded from the asset provider. Sessions whose balances
+// changed here are never evicted: the provider only knows the balances of the first load.
+func evictSessionTxn(txn *memdb.Txn, sessionID string) (bool, error) {
+	raw, err := txn.First("session_assets", "id", sessionID)
+	if err != nil || raw == nil {
+		return raw == nil && err == nil, err
+	}
+	if busy, err := sessionBusyTxn(txn, sessionID); err != nil || busy {
+		return false, err
+	}
+	if changed, err := txn.First("ledger", "session", sessionID); err != nil || changed != nil {
+		return false, err
+	}
+
+	session := raw.(*models.SessionAssets)
+	if err := txn.Delete("session_assets", session); err != nil {
+		return false, err
+	}
+	if remaining, err := txn.First("session_assets", "account", session.AccountID); err != nil {
+		return false, err
+	} else if remaining == nil {
+		if _, err := txn.DeleteAll("account_assets", "id", session.AccountID); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// sessionBusyTxn the session has orders waiting for their result or pending credits
+func sessionBusyTxn(txn *memdb.Txn, sessionID string) (bool, error) {
+	it, err := txn.Get("uuid_mapping", "session", sessionID)
+	if err != nil {
+		return false, err
+	}
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		if obj.(*UUIDMapping).Status == OrderStatusValidated {
+			return true, nil
+		}
+	}
+
+	it, err = txn.Get("reservation", "session", sessionID)
+	if err != nil {
+		return false, err
+	}
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		if obj.(*Reservation).Status == ReservationPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EvictIdleSessions remove sessions unused for IdleTTL, returning the number removed
+// Sessions with validated orders or pending credits are kept until they settle; sessions with
+// ledger entries are always kept.
+func EvictIdleSessions(now time.Time) (int, error) {
+	policy := getSessionPolicy()
+	if policy.IdleTTL <= 0 {
+		return 0, nil
+	}
+	database, err := GetDB()
+	if err != nil {
+		return 0, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	var evicted []string
+	for elem := sessionLRU.Back(); elem != nil; elem = elem.Prev() {
+		use := elem.Value.(*sessionUse)
+		if now.Sub(use.lastUsed) < policy.IdleTTL {
+			break
+		}
+		removed, err := evictSessionTxn(txn, use.sessionID)
+		if err != nil {
+			return 0, err
+		}
+		if removed {
+			evicted = append(evicted, use.sessionID)
+		}
+	}
+
+	for _, sessionID := range evicted {
+		forgetSessionLocked(sessionID)
+	}
+	txn.Commit()
+	recordEvictions(EvictionIdle, evicted)
+	return len(evicted), nil
+}
+
+// CountSessions number of stored sessions
+func CountSessions() int {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return sessionLRU.Len()
+}
+
+// recordEvictions log and count evicted sessions
+func recordEvictions(reason string, evicted []string) {
+	if len(evicted) == 0 {
+		return
+	}
+	metrics.SessionsEvicted.WithLabelValues(reason).Add(float64(len(evicted)))
+	slog.Info("EvictSessions", "reason", reason, "count", len(evicted), "sessionIDs", evicted)
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recognizingProvider 지정한 세션만 인식하는 테스트용 제공자
+type recognizingProvider struct {
+	known map[string]bool
+}
+
+func (p *recognizingProvider) LoadPlayer(ctx context.Context, sessionID string) (*provider.Player, error) {
+	return provider.NewDemoProvider().LoadPlayer(ctx, sessionID)
+}
+
+func (p *recognizingProvider) RecognizesSession(ctx context.Context, sessionID string) (bool, error) {
+	return p.known[sessionID], nil
+}
+
+func TestSessionAdmission(t *testing.T) {
+	require.NoError(t, InitDB())
+	defer CloseDB()
+
+	SetSessionPolicy(SessionPolicy{RequireKnown: true})
+	defer SetSessionPolicy(SessionPolicy{})
+
+	// 인식 기능이 없는 제공자는 등록되지 않은 세션을 만들지 않음
+	_, err := GetOrCreateSessionAssets("admission-unknown")
+	assert.True(t, errors.Is(err, ErrSessionNotRecognized), "%v", err)
+	assert.True(t, errors.Is(err, provider.ErrPlayerNotFound), "%v", err)
+	session, err := GetSessionAssets("admission-unknown")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+
+	// 지갑이 등록된 세션은 허용
+	_, err = StoreWalletEnrollment("admission-enrolled", "0x2222222222222222222222222222222222222222", "signature", "")
+	require.NoError(t, err)
+	_, err = GetOrCreateSessionAssets("admission-enrolled")
+	assert.NoError(t, err)
+
+	// 제공자가 인식하는 세션만 허용
+	SetAssetProvider(&recognizingProvider{known: map[string]bool{"admission-known": true}})
+	defer SetAssetProvider(provider.NewDemoProvider())
+	_, err = GetOrCreateSessionAssets("admission-known")
+	assert.NoError(t, err)
+	_, err = GetOrCreateSessionAssets("admission-stranger")
+	assert.True(t, errors.Is(err, ErrSessionNotRecognized), "%v", err)
+}
+
+func TestSessionEviction(t *testing.T) {
+	require.NoError(t, InitDB())
+	defer CloseDB()
+
+	// 다른 테스트가 남긴 유휴 세션 정리
+	SetSessionPolicy(SessionPolicy{IdleTTL: time.Nanosecond})
+	_, err := EvictIdleSessions(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	for _, sessionID := range []string{"evict-a", "evict-b", "evict-c", "evict-busy"} {
+		_, err := GetOrCreateSessionAssets(sessionID)
+		require.NoError(t, err)
+	}
+	require.NoError(t, StoreOrder(&UUIDMapping{UUID: "evict-busy-order", SessionID: "evict-busy", Status: OrderStatusValidated}))
+
+	// 가장 오래 사용하지 않은 세션부터 정리 (처리 중인 주문이 있는 세션은 유지)
+	_, err = GetOrCreateSessionAssets("evict-a")
+	require.NoError(t, err)
+	SetSessionPolicy(SessionPolicy{MaxSessions: CountSessions()})
+	defer SetSessionPolicy(SessionPolicy{})
+
+	_, err = GetOrCreateSessionAssets("evict-d")
+	require.NoError(t, err)
+	assertStored := func(sessionID string, stored bool) {
+		session, err := GetSessionAssets(sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, stored, session != nil, sessionID)
+	}
+	assertStored("evict-b", false)
+	assertStored("evict-a", true)
+	assertStored("evict-c", true)
+	assertStored("evict-d", true)
+	database, err := GetDB()
+	require.NoError(t, err)
+	txn := database.Txn(false)
+	account, err := txn.First("account_assets", "id", "evict-b")
+	txn.Abort()
+	require.NoError(t, err)
+	assert.Nil(t, account, "account of the last character is removed")
+
+	// 유휴 세션 정리
+	stored := CountSessions()
+	SetSessionPolicy(SessionPolicy{IdleTTL: time.Minute})
+	evicted, err := EvictIdleSessions(time.Now().Add(2 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 3, evicted)
+	assertStored("evict-busy", true)
+	assert.Equal(t, stored-3, CountSessions())
+
+	// 모든 세션이 사용 중이면 새 세션 거부
+	SetSessionPolicy(SessionPolicy{MaxSessions: CountSessions()})
+	_, err = GetOrCreateSessionAssets("evict-e")
+	assert.True(t, errors.Is(err, ErrSessionLimit), "%v", err)
+}
+
+func TestSessionEvictionKeepsChangedBalances(t *testing.T) {
+	require.NoError(t, InitDB())
+	defer CloseDB()
+
+	sessionID := "evict-ledger"
+	_, err := GetOrCreateSessionAssets(sessionID)
+	require.NoError(t, err)
+	require.NoError(t, CheckAndDeductAssets(sessionID, []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 100}}, LedgerRef{Source: LedgerSourceValidate}))
+	deducted, err := GetSessionAssets(sessionID)
+	require.NoError(t, err)
+
+	// 잔액이 바뀐 세션은 유휴 상태여도 정리하지 않음
+	SetSessionPolicy(SessionPolicy{IdleTTL: time.Minute})
+	defer SetSessionPolicy(SessionPolicy{})
+	_, err = EvictIdleSessions(time.Now().Add(2 * time.Minute))
+	require.NoError(t, err)
+
+	reloaded, err := GetOrCreateSessionAssets(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, deducted.Assets["asset_money"], reloaded.Assets["asset_money"])
+}
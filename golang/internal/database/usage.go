@@ -0,0 +1,162 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/hashicorp/go-memdb"
+)
+
+// StoreOrderUsage record asset usage of a validated order
+// Usage already recorded for the UUID belongs to another request with the same UUID and is never replaced.
+func StoreOrderUsage(usage *models.OrderUsage) error {
+	database, err := GetDB()
+	if err != nil {
+		return err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("order_usage", "id", usage.UUID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: usage of %s is already recorded", ErrOrderExists, usage.UUID)
+	}
+	if err := txn.Insert("order_usage", usage); err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteOrderUsage remove usage of an order that did not go through
+func DeleteOrderUsage(uuid string) error {
+	database, err := GetDB()
+	if err != nil {
+		return err
+	}
+
+	txn := database.Txn(true)
+	_, err = txn.DeleteAll("order_usage", "id", uuid)
+	if err != nil {
+		txn.Abort()
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// PurgeOrderUsage remove usage of purged orders created before the given time, returning the number removed
+// Usage of orders that are still stored is kept with the order.
+func PurgeOrderUsage(before time.Time) (int, error) {
+	database, err := GetDB()
+	if err != nil {
+		return 0, err
+	}
+
+	txn := database.Txn(true)
+	defer txn.Abort()
+
+	it, err := txn.Get("order_usage", "id")
+	if err != nil {
+		return 0, err
+	}
+	var stale []*models.OrderUsage
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		usage := obj.(*models.OrderUsage)
+		if !usage.CreatedAt.Before(before) {
+			continue
+		}
+		order, err := txn.First("uuid_mapping", "id", usage.UUID)
+		if err != nil {
+			return 0, err
+		}
+		if order == nil {
+			stale = append(stale, usage)
+		}
+	}
+	for _, usage := range stale {
+		if err := txn.Delete("order_usage", usage); err != nil {
+			return 0, err
+		}
+	}
+
+	txn.Commit()
+	return len(stale), nil
+}
+
+// restoreUsageTxn record the issuance of a late-confirmed reservation within write transaction
+// The usage keeps the reservation's creation time, so it counts in the window the order was validated.
+func restoreUsageTxn(txn *memdb.Txn, reservation *Reservation, assets []models.PairAsset) error {
+	existing, err := txn.First("order_usage", "id", reservation.UUID)
+	if err != nil || existing != nil {
+		return err
+	}
+
+	issued := make(map[string]uint64)
+	for _, asset := range assets {
+		issued[asset.AssetID] += uint64(asset.Amount)
+	}
+	return txn.Insert("order_usage", &models.OrderUsage{
+		UUID:       reservation.UUID,
+		ProjectID:  reservation.ProjectID,
+		SessionID:  reservation.SessionID,
+		IntentType: models.IntentTypeDisassemble,
+		Consumed:   map[string]uint64{},
+		Issued:     issued,
+		CreatedAt:  reservation.CreatedAt,
+	})
+}
+
+// ListCharacterUsage list usage of a character's orders created since the given time
+func ListCharacterUsage(projectID, sessionID string, since time.Time) ([]models.OrderUsage, error) {
+	usages, err := listOrderUsage("session", sessionID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := usages[:0]
+	for _, usage := range usages {
+		if usage.ProjectID == projectID {
+			filtered = append(filtered, usage)
+		}
+	}
+	return filtered, nil
+}
+
+// ListProjectUsage list usage of a project's orders created since the given time
+func ListProjectUsage(projectID string, since time.Time) ([]models.OrderUsage, error) {
+	return listOrderUsage("project", projectID, since)
+}
+
+// listOrderUsage list usage by index value created since the given time
+func listOrderUsage(index, value string, since time.Time) ([]models.OrderUsage, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := database.Txn(false)
+	defer txn.Abort()
+
+	it, err := txn.Get("order_usage", index, value)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []models.OrderUsage
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		usage := obj.(*models.OrderUsage)
+		if !usage.CreatedAt.Before(since) {
+			usages = append(usages, *usage)
+		}
+	}
+	return usages, nil
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/middleware"
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/services"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+)
+
+// Page size of admin listings
+const (
+	defaultAdminPageLimit = 50
+	maxAdminPageLimit     = 500
+)
+
+// adminSessionList session listing of the admin API
+type adminSessionList struct {
+	Total    int                     `json:"total"`
+	Offset   int                     `json:"offset"`
+	Limit    int                     `json:"limit"`
+	Sessions []*models.SessionAssets `json:"sessions"`
+}
+
+// adminSession session balances with the account's common balances
+type adminSession struct {
+	Session *models.SessionAssets `json:"session"`
+	Common  map[string]string     `json:"common"`
+}
+
+// adjustmentRequest manual credit (positive amount) or debit (negative amount)
+type adjustmentRequest struct {
+	AssetID string `json:"asset_id" binding:"required"`
+	Amount  int64  `json:"amount" binding:"required"`
+	Reason  string `json:"reason" binding:"required"`
+}
+
+// enrollmentRequest operator enrollment of a wallet with a session
+type enrollmentRequest struct {
+	WalletAddress string `json:"wallet_address" binding:"required"`
+	Reason        string `json:"reason" binding:"required"`
+}
+
+// orderActionRequest operator action on an order
+type orderActionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+	// TxHash transaction of the result (retry and mark settled)
+	TxHash string `json:"tx_hash"`
+	// Receipt receipt to settle with (retry only; fetched from the chain when omitted)
+	Receipt *ethTypes.Receipt `json:"receipt"`
+}
+
+// ListSessionsHandler list stored sessions (offset and limit query parameters)
+func ListSessionsHandler(c *gin.Context) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		ErrorResponse(c, ErrInvalidRequest.WithMessage("offset must be a non-negative integer"))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAdminPageLimit)))
+	if err != nil || limit < 1 || limit > maxAdminPageLimit {
+		ErrorResponse(c, ErrInvalidRequest.WithMessage("limit must be between 1 and "+strconv.Itoa(maxAdminPageLimit)))
+		return
+	}
+
+	sessions, total, err := database.ListSessions(offset, limit)
+	if err != nil {
+		LogError(middleware.Logger(c), "ListSessionsHandler", err)
+		ErrorResponse(c, LookupError(err, ErrDBError))
+		return
+	}
+	if sessions == nil {
+		sessions = []*models.SessionAssets{}
+	}
+
+	c.JSON(http.StatusOK, models.Response{Success: true, Data: adminSessionList{
+		Total:    total,
+		Offset:   offset,
+		Limit:    limit,
+		Sessions: sessions,
+	}})
+}
+
+// GetSessionHandler session balances and the account's common balances
+func GetSessionHandler(c *gin.Context) {
+	session, ok := loadAdminSession(c)
+	if !ok {
+		return
+	}
+	respondAdminSession(c, session)
+}
+
+// GetLedgerHandler balance changes made through the session, oldest first
+// Entries are kept after the session is evicted, so the session does not need to be stored.
+func GetLedgerHandler(c *gin.Context) {
+	entries, err := database.ListLedger(c.Param("sessionID"))
+	if err != nil {
+		LogError(middleware.Logger(c), "GetLedgerHandler", err)
+		ErrorResponse(c, LookupError(err, ErrDBError))
+		return
+	}
+	if entries == nil {
+		entries = []*database.LedgerEntry{}
+	}
+	c.JSON(http.StatusOK, models.Response{Success: true, Data: entries})
+}
+
+// ListSessionOrdersHandler orders of the session with their history, oldest first
+// Orders are kept after the session is evicted, so the session does not need to be stored.
+func ListSessionOrdersHandler(c *gin.Context) {
+	orders, err := database.ListOrdersBySession(c.Param("sessionID"))
+	if err != nil {
+		LogError(middleware.Logger(c), "ListSessionOrdersHandler", err)
+		ErrorResponse(c, LookupError(err, ErrDBError))
+		return
+	}
+	if orders == nil {
+		orders = []*database.UUIDMapping{}
+	}
+	c.JSON(http.StatusOK, models.Response{Success: true, Data: orders})
+}
+
+// GetOrderHandler order by UUID
+func GetOrderHandler(c *gin.Context) {
+	order, err := database.GetOrder(c.Param("uuid"))
+	respondAdminOrder(c, order, err)
+}
+
+// FindOrderHandler order by the tx_hash query parameter
+func FindOrderHandler(c *gin.Context) {
+	txHash := c.Query("tx_hash")
+	if txHash == "" {
+		ErrorResponse(c, ErrInvalidRequest.WithMessage("tx_hash query parameter is required"))
+		return
+	}
+	order, err := database.GetOrderByTxHash(txHash)
+	respondAdminOrder(c, order, err)
+}
+
+// AdjustBalanceHandler apply a manual credit or debit, recorded in the ledger with the operator and reason
+func AdjustBalanceHandler(c *gin.Context) {
+	var req adjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, ErrInvalidRequest.WithMessage(err.Error()))
+		return
+	}
+
+	sessionID := c.Param("sessionID")
+	session, err := services.AdjustBalance(sessionID, req.AssetID, req.Amount, middleware.Operator(c), req.Reason)
+	if err != nil {
+		apiErr := LookupError(err, ErrDBError)
+		LogError(middleware.Logger(c), "AdjustBalanceHandler", err, "sessionID", sessionID, "assetID", req.AssetID, "amount", req.Amount, "code", apiErr.Code)
+		ErrorResponse(c, apiErr)
+		return
+	}
+	respondAdminSession(c, session)
+}
+
+// ReplaceEnrollmentHandler enroll a wallet with a session on behalf of an operator, replacing any enrolled wallet
+func ReplaceEnrollmentHandler(c *gin.Context) {
+	var req enrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, ErrInvalidRequest.WithMessage(err.Error()))
+		return
+	}
+	if !common.IsHexAddress(req.WalletAddress) {
+		ErrorResponse(c, ErrInvalidRequest.WithMessage("wallet_address is not a hex address"))
+		return
+	}
+
+	sessionID := c.Param("sessionID")
+	enrollment, err := services.ReplaceEnrollment(sessionID, common.HexToAddress(req.WalletAddress), middleware.Operator(c), req.Reason)
+	if err != nil {
+		apiErr := LookupError(err, ErrDBError)
+		LogError(middleware.Logger(c), "ReplaceEnrollmentHandler", err, "sessionID", sessionID, "code", apiErr.Code)
+		ErrorResponse(c, apiErr)
+		return
+	}
+	c.JSON(http.StatusOK, models.Response{Success: true, Data: models.EnrollmentData{
+		SessionID:     sessionID,
+		WalletMapping: toWalletMapping(enrollment),
+	}})
+}
+
+// RetrySettlementHandler re-run the result path of an order with a supplied or fetched receipt
+func RetrySettlementHandler(c *gin.Context) {
+	req, ok := bindOrderAction(c)
+	if !ok {
+		return
+	}
+	order, err := services.RetrySettlement(c.Request.Context(), c.Param("uuid"), req.TxHash, req.Receipt, middleware.Operator(c), req.Reason)
+	respondOrderAction(c, "RetrySettlementHandler", order, err)
+}
+
+// ForceRefundHandler reject a validated order, or refund a failed one, returning its deducted assets
+func ForceRefundHandler(c *gin.Context) {
+	req, ok := bindOrderAction(c)
+	if !ok {
+		return
+	}
+	order, err := services.ForceRefund(c.Param("uuid"), middleware.Operator(c), req.Reason)
+	respondOrderAction(c, "ForceRefundHandler", order, err)
+}
+
+// MarkSettledHandler settle an order as a successful result without a receipt
+func MarkSettledHandler(c *gin.Context) {
+	req, ok := bindOrderAction(c)
+	if !ok {
+		return
+	}
+	order, err := services.MarkSettled(c.Request.Context(), c.Param("uuid"), req.TxHash, middleware.Operator(c), req.Reason)
+	respondOrderAction(c, "MarkSettledHandler", order, err)
+}
+
+// bindOrderAction bind an operator action request (answers 400 when invalid)
+func bindOrderAction(c *gin.Context) (*orderActionRequest, bool) {
+	var req orderActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, ErrInvalidRequest.WithMessage(err.Error()))
+		return nil, false
+	}
+	if req.TxHash != "" {
+		if hash, err := hexutil.Decode(req.TxHash); err != nil || len(hash) != common.HashLength {
+			ErrorResponse(c, ErrInvalidRequest.WithMessage("tx_hash must be a 32-byte hex string"))
+			return nil, false
+		}
+	}
+	middleware.AddLogFields(c, "uuid", c.Param("uuid"))
+	return &req, true
+}
+
+// respondOrderAction answer with the order after an operator action (unknown orders are 404)
+func respondOrderAction(c *gin.Context, handler string, order *database.UUIDMapping, err error) {
+	if err != nil {
+		apiErr := LookupError(err, ErrInternal)
+		if errors.Is(err, database.ErrOrderNotFound) {
+			apiErr = ErrOrderNotFound
+		}
+		LogError(middleware.Logger(c), handler, err, "code", apiErr.Code)
+		ErrorResponse(c, apiErr)
+		return
+	}
+	c.JSON(http.StatusOK, models.Response{Success: true, Data: order})
+}
+
+// loadAdminSession stored session of the sessionID path parameter (answers 404 when unknown)
+func loadAdminSession(c *gin.Context) (*models.SessionAssets, bool) {
+	sessionID := c.Param("sessionID")
+	session, err := database.GetSessionAssets(sessionID)
+	if err != nil {
+		LogError(middleware.Logger(c), "loadAdminSession", err, "sessionID", sessionID)
+		ErrorResponse(c, LookupError(err, ErrDBError))
+		return nil, false
+	}
+	if session == nil {
+		ErrorResponse(c, ErrSessionNotFound)
+		return nil, false
+	}
+	return session, true
+}
+
+// respondAdminSession answer with the session and its account's common balances
+func respondAdminSession(c *gin.Context, session *models.SessionAssets) {
+	account, err := database.GetOrCreateAccountAssets(session.AccountID)
+	if err != nil {
+		LogError(middleware.Logger(c), "respondAdminSession", err, "accountID", session.AccountID)
+		ErrorResponse(c, LookupError(err, ErrDBError))
+		return
+	}
+	c.JSON(http.StatusOK, models.Response{Success: true, Data: adminSession{Session: session, Common: account.Common}})
+}
+
+// respondAdminOrder answer with the order (404 when unknown)
+func respondAdminOrder(c *gin.Context, order *database.UUIDMapping, err error) {
+	if err != nil {
+		LogError(middleware.Logger(c), "respondAdminOrder", err)
+		ErrorResponse(c, LookupError(err, ErrDBError))
+		return
+	}
+	if order == nil {
+		ErrorResponse(c, ErrOrderNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, models.Response{Success: true, Data: order})
+}
@@ -1,17 +1,27 @@
 package handlers
 
 import (
-	"fmt"
-	"log/slog"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"sample-game-backend/internal/catalog"
 	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/middleware"
 	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Assets response versions
+const (
+	assetsVersionV1 = "v1"
+	assetsVersionV2 = "v2"
+)
+
 // GetAssetsHandler asset information retrieval handler
 func GetAssetsHandler(c *gin.Context) {
 	language := c.Query("language")
@@ -22,36 +32,30 @@ func GetAssetsHandler(c *gin.Context) {
 		return
 	}
 
-	// Validate language parameter
+	// Validate language parameter (the ramp UI uses "lang")
+	if language == "" {
+		language = c.Query("lang")
+	}
 	if language == "" {
-		language = "ko" // Default value
+		language = catalog.LanguageKorean // Default value
+	}
+	language = catalog.NormalizeLanguage(language)
+
+	// Negotiate response version (v1 by default)
+	version, ok := negotiateAssetsVersion(c)
+	if !ok {
+		ErrorResponse(c, ErrUnsupportedVersion)
+		return
 	}
 
 	// Get or create session-specific asset information
 	sessionAssets, err := database.GetOrCreateSessionAssets(sessionID)
 	if err != nil {
-		LogError(slog.Default(), "GetAssetsHandler", err, "sessionID", sessionID)
-		ErrorResponse(c, http.StatusInternalServerError, ErrorCodeDBError)
+		LogError(middleware.Logger(c), "GetAssetsHandler", err)
+		ErrorResponse(c, LookupError(err, ErrDBError))
 		return
 	}
 
-	// Convert to Asset struct
-	var assets []models.Asset
-	for id, balance := range sessionAssets.Assets {
-		assets = append(assets, models.Asset{
-			ID:      id,
-			Balance: balance,
-		})
-	}
-
-	v1Data := models.V1Data{
-		PlayerID:      sessionID,
-		Name:          fmt.Sprintf("playerName_%s", sessionID),
-		WalletAddress: "0xaaaa",
-		Server:        "test",
-		Assets:        assets,
-	}
-
 	// Parse session information
 	createdAt, _ := time.Parse(time.RFC3339, sessionAssets.CreatedAt)
 	updatedAt, _ := time.Parse(time.RFC3339, sessionAssets.UpdatedAt)
@@ -79,17 +83,169 @@ func GetAssetsHandler(c *gin.Context) {
 		},
 	}
 
+	// Wallet mapping status (the enrolled wallet replaces the provider's wallet address)
+	walletMapping, err := GetWalletMapping(sessionID)
+	if err != nil {
+		LogError(middleware.Logger(c), "GetAssetsHandler", err, "action", "Failed to get wallet mapping")
+		ErrorResponse(c, ErrDBError)
+		return
+	}
+	walletAddress := sessionAssets.WalletAddress
+	if walletMapping.Status == models.WalletMappingEnrolled {
+		walletAddress = walletMapping.WalletAddress
+	}
+
+	var data any
+	switch version {
+	case assetsVersionV2:
+		v2Data, err := buildV2Data(sessionAssets, language)
+		if err != nil {
+			LogError(middleware.Logger(c), "GetAssetsHandler", err, "version", version)
+			ErrorResponse(c, ErrDBError)
+			return
+		}
+		data = models.AssetsV2Data{
+			V2:            *v2Data,
+			WalletMapping: walletMapping,
+			Guide:         guide,
+		}
+	default:
+		pending, err := buildPendingList(sessionID, language)
+		if err != nil {
+			LogError(middleware.Logger(c), "GetAssetsHandler", err, "action", "Failed to get pending credits")
+			ErrorResponse(c, ErrDBError)
+			return
+		}
+		data = models.AssetsV1Data{
+			V1: models.V1Data{
+				PlayerID:      sessionID,
+				Name:          sessionAssets.Name,
+				WalletAddress: walletAddress,
+				Server:        sessionAssets.Server,
+				Assets:        buildAssetList(sessionAssets.Assets, language),
+				Pending:       pending,
+			},
+			WalletMapping: walletMapping,
+			Guide:         guide,
+		}
+	}
+
 	response := models.Response{
 		Success:   true,
 		ErrorCode: nil,
-		Data: struct {
-			V1    models.V1Data `json:"v1"`
-			Guide any           `json:"guide"`
-		}{
-			V1:    v1Data,
-			Guide: guide,
-		},
+		Data:      data,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// negotiateAssetsVersion resolve response version from "version" query or X-Assets-Version header
+func negotiateAssetsVersion(c *gin.Context) (string, bool) {
+	version := c.Query("version")
+	if version == "" {
+		version = c.GetHeader("X-Assets-Version")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(version)) {
+	case "", "1", assetsVersionV1:
+		return assetsVersionV1, true
+	case "2", assetsVersionV2:
+		return assetsVersionV2, true
+	default:
+		return "", false
+	}
+}
+
+// buildV2Data build v2 data with account common balances and every character of the account
+func buildV2Data(sessionAssets *models.SessionAssets, language string) (*models.V2Data, error) {
+	accountAssets, err := database.GetOrCreateAccountAssets(sessionAssets.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	characters, err := database.ListAccountCharacters(sessionAssets.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	v2Data := &models.V2Data{
+		Common:     buildAssetList(accountAssets.Common, language),
+		Characters: make([]models.V2Character, 0, len(characters)),
+	}
+	for _, character := range characters {
+		walletAccount := character.WalletAddress
+		enrollment, err := database.GetWalletEnrollment(character.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if enrollment != nil {
+			walletAccount = enrollment.WalletAddress
+		}
+		pending, err := buildPendingList(character.SessionID, language)
+		if err != nil {
+			return nil, err
+		}
+
+		v2Data.Characters = append(v2Data.Characters, models.V2Character{
+			CharacterID:   character.SessionID,
+			Name:          character.Name,
+			ImageURL:      character.ImageURL,
+			WalletAccount: walletAccount,
+			Server:        character.Server,
+			Inventory:     buildAssetList(character.Assets, language),
+			Pending:       pending,
+		})
+	}
+
+	return v2Data, nil
+}
+
+// buildPendingList list pending credits of the session (nil when nothing is pending)
+func buildPendingList(sessionID, language string) ([]models.Asset, error) {
+	pending, err := services.PendingCredits(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	balances := make(map[string]string, len(pending))
+	for id, amount := range pending {
+		balances[id] = strconv.FormatUint(amount, 10)
+	}
+	return buildAssetList(balances, language), nil
+}
+
+// buildAssetList convert balances to assets with localized metadata
+// Catalog assets come first in catalog order, unknown assets follow sorted by ID
+func buildAssetList(balances map[string]string, language string) []models.Asset {
+	c := catalog.Get()
+
+	assets := make([]models.Asset, 0, len(balances))
+	for _, id := range c.IDs() {
+		if balance, exists := balances[id]; exists {
+			assets = append(assets, models.Asset{
+				ID:       id,
+				Balance:  balance,
+				Metadata: c.Metadata(id, language),
+			})
+		}
+	}
+
+	var unknown []string
+	for id := range balances {
+		if _, ok := c.Lookup(id); !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	for _, id := range unknown {
+		assets = append(assets, models.Asset{
+			ID:      id,
+			Balance: balances[id],
+		})
+	}
+
+	return assets
+}
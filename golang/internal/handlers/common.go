@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"sample-game-backend/internal/metrics"
 	"sample-game-backend/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -18,24 +19,83 @@ const (
 	ErrorCodeUUIDMappingFailed   = "UUID_MAPPING_FAILED"
 	ErrorCodeInsufficientBalance = "INSUFFICIENT_BALANCE"
 	ErrorCodeSignatureGeneration = "SIGNATURE_GENERATION_FAILED"
+	ErrorCodeUnsupportedVersion  = "UNSUPPORTED_VERSION"
+	ErrorCodeInvalidUser         = "INVALID_USER"
+	ErrorCodeEnrollmentFailed    = "ENROLLMENT_VERIFICATION_FAILED"
+	ErrorCodeWalletNotEnrolled   = "WALLET_NOT_ENROLLED"
+	ErrorCodeWalletMismatch      = "WALLET_MISMATCH"
+	ErrorCodeWalletEnrolled      = "WALLET_ALREADY_ENROLLED"
+	ErrorCodeDuplicateUUID       = "DUPLICATE_UUID"
+	ErrorCodeInvalidUUID         = "INVALID_UUID"
+	ErrorCodeIntentMismatch      = "INTENT_MISMATCH"
+	ErrorCodeReceiptNotConfirmed = "RECEIPT_NOT_CONFIRMED"
+	ErrorCodeInternal            = "INTERNAL_ERROR"
+	ErrorCodeAssetNotFound       = "ASSET_NOT_FOUND"
+	ErrorCodeCorruptBalance      = "CORRUPT_BALANCE"
+	ErrorCodeAmountOutOfRange    = "AMOUNT_OUT_OF_RANGE"
+	ErrorCodeDBNotInitialized    = "DB_NOT_INITIALIZED"
+	ErrorCodeUnauthorized        = "UNAUTHORIZED"
+	ErrorCodeSessionNotFound     = "SESSION_NOT_FOUND"
+	ErrorCodeOrderNotFound       = "ORDER_NOT_FOUND"
+	ErrorCodeInvalidAdjustment   = "INVALID_ADJUSTMENT"
+	ErrorCodeReasonRequired      = "REASON_REQUIRED"
+	ErrorCodeReceiptUnavailable  = "RECEIPT_UNAVAILABLE"
+	ErrorCodeOrderStatusConflict = "ORDER_STATUS_CONFLICT"
+	ErrorCodeNoValidatedIntent   = "NO_VALIDATED_INTENT"
+	ErrorCodeRateLimited         = "RATE_LIMITED"
+	ErrorCodeSessionLimit        = "SESSION_LIMIT_REACHED"
+	ErrorCodeClientCertRequired  = "CLIENT_CERT_REQUIRED"
+)
+
+// Webhook error codes defined by the CROSS RAMP guide
+const (
+	ErrorCodeInvalidBalance = "INVALID_BALANCE"
+	ErrorCodeInvalidMessage = "INVALID_MESSAGE"
 )
 
 // ErrorResponse creates a standard error response
-func ErrorResponse(c *gin.Context, statusCode int, errorCode string) {
-	response := models.Response{
+func ErrorResponse(c *gin.Context, apiErr *APIError) {
+	metrics.SetErrorCode(c, apiErr.Code)
+	c.JSON(apiErr.Status, models.Response{
 		Success:   false,
-		ErrorCode: &errorCode,
-	}
-	c.JSON(statusCode, response)
+		ErrorCode: &apiErr.Code,
+		Message:   problemDescription(apiErr),
+	})
+}
+
+// AbortWithError aborts the request chain with a standard error response (for middleware)
+func AbortWithError(c *gin.Context, apiErr *APIError) {
+	ErrorResponse(c, apiErr)
+	c.Abort()
 }
 
 // ValidateErrorResponse creates a standard validate error response
-func ValidateErrorResponse(c *gin.Context, statusCode int, errorCode string) {
-	response := models.ValidateResponse{
+func ValidateErrorResponse(c *gin.Context, apiErr *APIError) {
+	metrics.SetErrorCode(c, apiErr.Code)
+	c.JSON(apiErr.Status, models.ValidateResponse{
 		Success:   false,
-		ErrorCode: &errorCode,
+		ErrorCode: &apiErr.Code,
+		Message:   problemDescription(apiErr),
+	})
+}
+
+// ValidateFieldErrorResponse creates a validate error response with field-level details
+func ValidateFieldErrorResponse(c *gin.Context, apiErr *APIError, details []models.FieldError) {
+	metrics.SetErrorCode(c, apiErr.Code)
+	c.JSON(apiErr.Status, models.ValidateResponse{
+		Success:   false,
+		ErrorCode: &apiErr.Code,
+		Message:   problemDescription(apiErr),
+		Details:   details,
+	})
+}
+
+// problemDescription human-readable description returned with client errors (4xx only)
+func problemDescription(apiErr *APIError) string {
+	if apiErr.Status >= http.StatusInternalServerError {
+		return ""
 	}
-	c.JSON(statusCode, response)
+	return apiErr.Message
 }
 
 // LogError logs an error with consistent formatting
@@ -57,7 +117,7 @@ func GetSessionIDFromContext(c *gin.Context) string {
 func ValidateSessionID(c *gin.Context) (string, bool) {
 	sessionID := GetSessionIDFromContext(c)
 	if sessionID == "" {
-		ErrorResponse(c, http.StatusBadRequest, ErrorCodeInvalidSessionID)
+		ErrorResponse(c, ErrInvalidSessionID)
 		return "", false
 	}
 	return sessionID, true
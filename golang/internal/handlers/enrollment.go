@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/middleware"
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/services"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// GetEnrollmentHandler wallet enrollment status handler
+// With wallet_address query, also returns the message to sign for enrollment
+func GetEnrollmentHandler(c *gin.Context) {
+	sessionID, valid := ValidateSessionID(c)
+	if !valid {
+		return
+	}
+
+	mapping, err := GetWalletMapping(sessionID)
+	if err != nil {
+		LogError(middleware.Logger(c), "GetEnrollmentHandler", err)
+		ErrorResponse(c, ErrDBError)
+		return
+	}
+
+	data := models.EnrollmentData{
+		SessionID:     sessionID,
+		WalletMapping: mapping,
+	}
+
+	if walletAddress := c.Query("wallet_address"); walletAddress != "" {
+		if !common.IsHexAddress(walletAddress) {
+			ErrorResponse(c, ErrInvalidRequest.WithMessage("wallet_address is not a hex address"))
+			return
+		}
+		data.Message = services.BuildEnrollmentMessage(sessionID, common.HexToAddress(walletAddress), time.Now())
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// EnrollWalletHandler wallet enrollment handler
+func EnrollWalletHandler(c *gin.Context) {
+	sessionID, valid := ValidateSessionID(c)
+	if !valid {
+		return
+	}
+
+	var req models.EnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, ErrInvalidRequest.WithMessage(err.Error()))
+		return
+	}
+
+	// Verify wallet ownership before the session is loaded, so proven players are admitted
+	var wallet common.Address
+	var err error
+	switch req.Method {
+	case "", services.EnrollmentMethodSignature:
+		req.Method = services.EnrollmentMethodSignature
+		wallet, err = services.VerifyEnrollmentSignature(sessionID, req.WalletAddress, req.Message, req.Signature)
+	case services.EnrollmentMethodJWT:
+		wallet, err = services.VerifyCrossAuthJWT(sessionID, c.GetString("Authorization"))
+	default:
+		ErrorResponse(c, ErrInvalidRequest.WithMessage("unsupported enrollment method"))
+		return
+	}
+	if err != nil {
+		LogError(middleware.Logger(c), "EnrollWalletHandler", err, "method", req.Method)
+		if errors.Is(err, services.ErrEnrollmentJWTNotSupported) {
+			ErrorResponse(c, ErrInvalidRequest.WithMessage(err.Error()))
+			return
+		}
+		ErrorResponse(c, ErrEnrollmentFailed.WithMessage(err.Error()))
+		return
+	}
+
+	// Replacing a different enrolled wallet needs that wallet's signature over the enrollment message
+	var replaces string
+	if req.CurrentWalletSignature != "" {
+		current, err := services.VerifyWalletChange(sessionID, wallet, req.Message, req.CurrentWalletSignature)
+		if err != nil {
+			LogError(middleware.Logger(c), "EnrollWalletHandler", err, "action", "Failed to verify wallet change")
+			if errors.Is(err, services.ErrEnrollmentVerification) {
+				ErrorResponse(c, ErrEnrollmentFailed.WithMessage(err.Error()))
+				return
+			}
+			ErrorResponse(c, LookupError(err, ErrDBError))
+			return
+		}
+		replaces = current.Hex()
+	}
+
+	enrollment, err := services.EnrollWallet(sessionID, wallet, req.Method, replaces)
+	if err != nil {
+		LogError(middleware.Logger(c), "EnrollWalletHandler", err, "action", "Failed to load player or store enrollment")
+		ErrorResponse(c, LookupError(err, ErrDBError))
+		return
+	}
+
+	LogInfo(middleware.Logger(c), "EnrollWalletHandler", "walletAddress", enrollment.WalletAddress, "method", enrollment.Method)
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Data: models.EnrollmentData{
+			SessionID:     sessionID,
+			WalletMapping: toWalletMapping(enrollment),
+		},
+	})
+}
+
+// GetWalletMapping get wallet mapping status of session
+func GetWalletMapping(sessionID string) (*models.WalletMapping, error) {
+	enrollment, err := database.GetWalletEnrollment(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return toWalletMapping(enrollment), nil
+}
+
+// toWalletMapping convert enrollment to response structure
+func toWalletMapping(enrollment *database.WalletEnrollment) *models.WalletMapping {
+	if enrollment == nil {
+		return &models.WalletMapping{Status: models.WalletMappingNotEnrolled}
+	}
+	return &models.WalletMapping{
+		Status:        models.WalletMappingEnrolled,
+		WalletAddress: enrollment.WalletAddress,
+		Method:        enrollment.Method,
+		EnrolledAt:    enrollment.EnrolledAt,
+	}
+}
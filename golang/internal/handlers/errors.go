@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/provider"
+	"sample-game-backend/internal/receipt"
+	"sample-game-backend/internal/services"
+)
+
+// APIError error catalog entry: HTTP status, response code and problem description
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// WithMessage copy of the entry with a more specific problem description
+func (e *APIError) WithMessage(message string) *APIError {
+	copied := *e
+	copied.Message = message
+	return &copied
+}
+
+// badRequest create a 400 entry for codes defined outside the catalog (rules, conversion, receipt)
+func badRequest(code, message string) *APIError {
+	return &APIError{Status: http.StatusBadRequest, Code: code, Message: message}
+}
+
+// Error catalog
+var (
+	ErrInvalidRequest      = &APIError{http.StatusBadRequest, ErrorCodeInvalidRequest, "Request does not match the API specification"}
+	ErrInvalidSessionID    = &APIError{http.StatusBadRequest, ErrorCodeInvalidSessionID, "X-Dapp-SessionID header is missing"}
+	ErrInvalidUser         = &APIError{http.StatusBadRequest, ErrorCodeInvalidUser, "Invalid game user"}
+	ErrInvalidBalance      = &APIError{http.StatusBadRequest, ErrorCodeInvalidBalance, "Insufficient game user currency"}
+	ErrInvalidMessage      = &APIError{http.StatusBadRequest, ErrorCodeInvalidMessage, "Message authentication code mismatch"}
+	ErrInvalidIntent       = &APIError{http.StatusBadRequest, ErrorCodeInvalidIntent, "Intent is not valid"}
+	ErrInvalidUUID         = &APIError{http.StatusBadRequest, ErrorCodeInvalidUUID, "Invalid UUID or session not found"}
+	ErrDuplicateUUID       = &APIError{http.StatusBadRequest, ErrorCodeDuplicateUUID, "UUID was already validated"}
+	ErrIntentMismatch      = &APIError{http.StatusBadRequest, ErrorCodeIntentMismatch, "Intent does not match the validated order"}
+	ErrInsufficientBalance = &APIError{http.StatusBadRequest, ErrorCodeInsufficientBalance, "Insufficient in-game assets for the intent"}
+	ErrAssetNotFound       = &APIError{http.StatusBadRequest, ErrorCodeAssetNotFound, "Game user does not hold an asset of the intent"}
+	ErrAmountOutOfRange    = &APIError{http.StatusBadRequest, ErrorCodeAmountOutOfRange, "Asset amount or resulting balance is out of range"}
+	ErrUnsupportedVersion  = &APIError{http.StatusBadRequest, ErrorCodeUnsupportedVersion, "Unsupported assets API version"}
+	ErrWalletNotEnrolled   = &APIError{http.StatusBadRequest, ErrorCodeWalletNotEnrolled, "No wallet is enrolled for the session"}
+	ErrWalletMismatch      = &APIError{http.StatusBadRequest, ErrorCodeWalletMismatch, "user_address is not the session's enrolled wallet"}
+	ErrEnrollmentFailed    = &APIError{http.StatusUnauthorized, ErrorCodeEnrollmentFailed, "Wallet ownership could not be verified"}
+	ErrInvalidAdjustment   = &APIError{http.StatusBadRequest, ErrorCodeInvalidAdjustment, "Adjustment needs a non-zero amount, a reason and a catalog asset"}
+	ErrReasonRequired      = &APIError{http.StatusBadRequest, ErrorCodeReasonRequired, "Operator actions need a reason"}
+	ErrReceiptUnavailable  = &APIError{http.StatusBadRequest, ErrorCodeReceiptUnavailable, "No receipt supplied and none can be fetched"}
+	ErrUnauthorized        = &APIError{http.StatusUnauthorized, ErrorCodeUnauthorized, "Missing or invalid admin API key"}
+	ErrClientCertRequired  = &APIError{http.StatusUnauthorized, ErrorCodeClientCertRequired, "Valid CROSS RAMP client certificate required"}
+	ErrSessionNotFound     = &APIError{http.StatusNotFound, ErrorCodeSessionNotFound, "Session not found"}
+	ErrOrderNotFound       = &APIError{http.StatusNotFound, ErrorCodeOrderNotFound, "Order not found"}
+	ErrOrderStatusConflict = &APIError{http.StatusConflict, ErrorCodeOrderStatusConflict, "Operation does not apply to the order's status"}
+	ErrNoValidatedIntent   = &APIError{http.StatusConflict, ErrorCodeNoValidatedIntent, "Order was stored without a validated intent"}
+	ErrWalletEnrolled      = &APIError{http.StatusConflict, ErrorCodeWalletEnrolled, "A different wallet is enrolled; the change needs its signature or an operator"}
+	ErrRateLimited         = &APIError{http.StatusTooManyRequests, ErrorCodeRateLimited, "Too many requests, retry after the Retry-After delay"}
+	ErrReceiptNotConfirmed = &APIError{http.StatusServiceUnavailable, ErrorCodeReceiptNotConfirmed, "Transaction not confirmed yet"}
+	ErrDBError             = &APIError{http.StatusInternalServerError, ErrorCodeDBError, "Database error"}
+	ErrCorruptBalance      = &APIError{http.StatusInternalServerError, ErrorCodeCorruptBalance, "Stored balance is not a number"}
+	ErrSessionLimit        = &APIError{http.StatusServiceUnavailable, ErrorCodeSessionLimit, "No room for a new session, retry later"}
+	ErrDBNotInitialized    = &APIError{http.StatusServiceUnavailable, ErrorCodeDBNotInitialized, "Database is not initialized"}
+	ErrUUIDMappingFailed   = &APIError{http.StatusInternalServerError, ErrorCodeUUIDMappingFailed, "Failed to store the order"}
+	ErrSignatureGeneration = &APIError{http.StatusInternalServerError, ErrorCodeSignatureGeneration, "Failed to generate the validator signature"}
+	ErrInternal            = &APIError{http.StatusInternalServerError, ErrorCodeInternal, "Internal server error"}
+)
+
+// domainErrors domain errors mapped to catalog entries (first match wins)
+var domainErrors = []struct {
+	target error
+	apiErr *APIError
+}{
+	{provider.ErrPlayerNotFound, ErrInvalidUser},
+	{database.ErrOrderNotFound, ErrInvalidUUID},
+	{database.ErrOrderExists, ErrDuplicateUUID},
+	{services.ErrIntentMismatch, ErrIntentMismatch},
+	{services.ErrWalletNotEnrolled, ErrWalletNotEnrolled},
+	{services.ErrWalletMismatch, ErrWalletMismatch},
+	{database.ErrWalletAlreadyEnrolled, ErrWalletEnrolled},
+	{receipt.ErrNotConfirmed, ErrReceiptNotConfirmed},
+	{database.ErrInsufficientBalance, ErrInsufficientBalance},
+	{database.ErrAssetNotFound, ErrAssetNotFound},
+	{database.ErrCorruptBalance, ErrCorruptBalance},
+	{database.ErrAmountOutOfRange, ErrAmountOutOfRange},
+	{database.ErrNotInitialized, ErrDBNotInitialized},
+	{database.ErrSessionNotFound, ErrSessionNotFound},
+	{services.ErrInvalidAdjustment, ErrInvalidAdjustment},
+	{services.ErrReasonRequired, ErrReasonRequired},
+	{services.ErrReceiptUnavailable, ErrReceiptUnavailable},
+	{services.ErrNoValidatedIntent, ErrNoValidatedIntent},
+	{database.ErrOrderStatusStale, ErrOrderStatusConflict},
+	{database.ErrSessionLimit, ErrSessionLimit},
+}
+
+// LookupError map a domain error to its catalog entry (fallback when the error is not cataloged)
+func LookupError(err error, fallback *APIError) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var mismatch *receipt.Mismatch
+	if errors.As(err, &mismatch) {
+		return badRequest(mismatch.Code, mismatch.Message)
+	}
+	for _, entry := range domainErrors {
+		if errors.Is(err, entry.target) {
+			return entry.apiErr
+		}
+	}
+	return fallback
+}
@@ -1,10 +1,11 @@
 package handlers
 
 import (
-	"log/slog"
 	"net/http"
 
 	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/metrics"
+	"sample-game-backend/internal/middleware"
 	"sample-game-backend/internal/models"
 	"sample-game-backend/internal/services"
 
@@ -16,32 +17,33 @@ func ExchangeResultHandler(c *gin.Context) {
 	// Read request body
 	var req models.ExchangeReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		LogError(slog.Default(), "ResultHandler", err, "action", "Failed to bind request body")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to bind request body"})
+		LogError(middleware.Logger(c), "ResultHandler", err, "action", "Failed to bind request body")
+		ErrorResponse(c, ErrInvalidRequest.WithMessage(err.Error()))
 		return
 	}
 
-	// Log request body
-	LogInfo(slog.Default(), "ResultHandler", "requestBody", req)
-
-	if req.Intent.Type == "disassemble" && len(req.Intent.To) > 0 {
-		// Get SessionID by UUID
-		sessionID, err := database.GetSessionIDByUUID(req.UUID)
-		if err != nil {
-			LogError(slog.Default(), "ResultHandler", err, "action", "Failed to get session ID by UUID", "uuid", req.UUID)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID or session not found"})
-			return
-		}
+	// Correlate with the order's validate logs
+	var projectID, sessionID string
+	if order, err := database.GetOrder(req.UUID); err == nil && order != nil {
+		projectID, sessionID = order.ProjectID, order.SessionID
+	}
+	metrics.SetLabels(c, projectID, req.Intent.Type, req.Intent.Method)
+	middleware.AddLogFields(c, "sessionID", sessionID, "projectID", projectID, "uuid", req.UUID)
 
-		// Process exchange result
-		receiptStatus := uint64(req.Receipt.Status)
-		err = services.ProcessExchangeResult(sessionID, req.Intent.To, receiptStatus)
-		if err != nil {
-			LogError(slog.Default(), "ResultHandler", err, "action", "Failed to process exchange result")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exchange result"})
-			return
+	// Log request body
+	LogInfo(middleware.Logger(c), "ResultHandler", "requestBody", req)
+
+	// Verify receipt against tx_hash and the stored order, then process exchange result
+	if err := services.ProcessResult(c.Request.Context(), req); err != nil {
+		apiErr := LookupError(err, ErrInternal)
+		if apiErr == ErrInsufficientBalance {
+			// The result webhook reports balance failures with the guide's INVALID_BALANCE
+			apiErr = ErrInvalidBalance
 		}
+		LogError(middleware.Logger(c), "ResultHandler", err, "action", "Failed to process exchange result", "code", apiErr.Code)
+		ErrorResponse(c, apiErr)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	c.JSON(http.StatusOK, models.Response{Success: true})
 }
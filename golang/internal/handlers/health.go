@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout time one readiness check may take
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck dependency check run by /ready (nil when the dependency is usable)
+type ReadinessCheck func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+var (
+	readinessChecks []namedCheck
+	readinessMu     sync.RWMutex
+
+	// draining set once shutdown starts so load balancers stop routing new requests
+	draining atomic.Bool
+)
+
+// RegisterReadinessCheck add a check reported by /ready under name
+func RegisterReadinessCheck(name string, check ReadinessCheck) {
+	readinessMu.Lock()
+	defer readinessMu.Unlock()
+	readinessChecks = append(readinessChecks, namedCheck{name: name, check: check})
+}
+
+// SetDraining report the server as not ready while in-flight requests drain
+func SetDraining() {
+	draining.Store(true)
+}
+
+// HealthHandler liveness probe (the process is up and serving requests)
+func HealthHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "healthy",
+		"message": "Server is running normally",
+	})
+}
+
+// ReadyHandler readiness probe (the signer, storage and configuration are usable)
+func ReadyHandler(c *gin.Context) {
+	readinessMu.RLock()
+	checks := append([]namedCheck(nil), readinessChecks...)
+	readinessMu.RUnlock()
+
+	ready := !draining.Load()
+	results := make(map[string]string, len(checks))
+	for _, nc := range checks {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		err := nc.check(ctx)
+		cancel()
+		if err != nil {
+			ready = false
+			results[nc.name] = err.Error()
+			continue
+		}
+		results[nc.name] = "ok"
+	}
+
+	if !ready {
+		status := "not ready"
+		if draining.Load() {
+			status = "draining"
+		}
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": status, "checks": results})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": results})
+}
@@ -1,12 +1,11 @@
 package handlers
 
 import (
-	"net/http"
-
 	"sample-game-backend/internal/middleware"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // SetupRoutes configure router
@@ -16,14 +15,14 @@ func SetupRoutes(r *gin.Engine) {
 	{
 		// Endpoints requiring authentication
 		assets := api.Group("/assets")
-		assets.Use(middleware.AuthMiddleware())
+		assets.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware(rejectRateLimited))
 		{
 			assets.GET("", GetAssetsHandler)
 		}
 
 		// User action validation endpoints
 		validate := api.Group("/validate")
-		validate.Use(middleware.AuthMiddleware())
+		validate.Use(middleware.ClientCertMiddleware(rejectClientCert), middleware.AuthMiddleware(), middleware.WebhookRateLimitMiddleware(rejectRateLimited), middleware.HMACMiddleware(rejectInvalidMessage))
 		{
 			validate.POST("", ValidateUserActionHandler)
 		}
@@ -33,27 +32,60 @@ func SetupRoutes(r *gin.Engine) {
 			AllowOrigins: []string{"*"},
 			AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 			AllowHeaders: []string{"Authorization", "X-Dapp-Authorization", "X-Dapp-SessionID", "Content-Type", "ORIGIN", "Content-Length", "Content-Type", "Access-Control-Allow-Headers", "Access-Control-Allow-Origin", "Authorization", "X-Requested-With", "expires"},
-		}))
+		}), middleware.ClientCertMiddleware(rejectClientCert), middleware.HMACMiddleware(rejectInvalidMessage))
 		{
 			result.POST("", ExchangeResultHandler)
 		}
 
 		enrole := api.Group("/enrole")
-		enrole.Use(middleware.AuthMiddleware())
+		enrole.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware(rejectRateLimited))
 		{
-			enrole.GET("", func(c *gin.Context) {
-				c.JSON(http.StatusOK, gin.H{
-					"message": "Success",
-				})
-			})
+			enrole.GET("", GetEnrollmentHandler)
+			enrole.POST("", EnrollWalletHandler)
 		}
 	}
 
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "healthy",
-			"message": "Server is running normally",
-		})
-	})
+	// Support staff endpoints (operator API key required)
+	admin := r.Group("/admin")
+	admin.Use(middleware.AdminAuthMiddleware(rejectUnauthorized))
+	{
+		admin.GET("/sessions", ListSessionsHandler)
+		admin.GET("/sessions/:sessionID", GetSessionHandler)
+		admin.GET("/sessions/:sessionID/ledger", GetLedgerHandler)
+		admin.GET("/sessions/:sessionID/orders", ListSessionOrdersHandler)
+		admin.POST("/sessions/:sessionID/adjustments", AdjustBalanceHandler)
+		admin.PUT("/sessions/:sessionID/enrollment", ReplaceEnrollmentHandler)
+		admin.GET("/orders", FindOrderHandler)
+		admin.GET("/orders/:uuid", GetOrderHandler)
+		admin.POST("/orders/:uuid/retry", RetrySettlementHandler)
+		admin.POST("/orders/:uuid/refund", ForceRefundHandler)
+		admin.POST("/orders/:uuid/settle", MarkSettledHandler)
+	}
+
+	// Prometheus metrics endpoint
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Liveness and readiness endpoints
+	r.GET("/health", HealthHandler)
+	r.GET("/ready", ReadyHandler)
+}
+
+// rejectInvalidMessage answer requests whose X-HMAC-SIGNATURE does not match the body
+func rejectInvalidMessage(c *gin.Context) {
+	AbortWithError(c, ErrInvalidMessage)
+}
+
+// rejectRateLimited answer requests throttled by the rate limiter
+func rejectRateLimited(c *gin.Context) {
+	AbortWithError(c, ErrRateLimited)
+}
+
+// rejectClientCert answer CROSS RAMP requests without an accepted client certificate
+func rejectClientCert(c *gin.Context) {
+	AbortWithError(c, ErrClientCertRequired)
+}
+
+// rejectUnauthorized answer admin requests without a valid operator API key
+func rejectUnauthorized(c *gin.Context) {
+	AbortWithError(c, ErrUnauthorized)
 }
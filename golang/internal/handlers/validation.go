@@ -2,16 +2,25 @@ package handlers
 
 import (
 	"encoding/json"
-	"log/slog"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
+	"sample-game-backend/internal/audit"
+	"sample-game-backend/internal/conversion"
 	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/metrics"
+	"sample-game-backend/internal/middleware"
 	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/rules"
 	"sample-game-backend/internal/services"
+	"sample-game-backend/internal/tracing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ValidateUserActionHandler user action validation handler
@@ -20,13 +29,36 @@ func ValidateUserActionHandler(c *gin.Context) {
 
 	// Request binding and validation
 	if err := c.ShouldBindJSON(&req); err != nil {
-		ValidateErrorResponse(c, http.StatusBadRequest, ErrorCodeInvalidRequest)
+		ValidateErrorResponse(c, ErrInvalidRequest.WithMessage(err.Error()))
 		return
 	}
+	metrics.SetLabels(c, req.ProjectID, req.Intent.Type, req.Intent.Method)
+	middleware.AddLogFields(c, "projectID", req.ProjectID, "uuid", req.UUID)
 
-	// Intent validation
-	if !services.ValidateIntent(req.Intent) {
-		ValidateErrorResponse(c, http.StatusBadRequest, ErrorCodeInvalidIntent)
+	// Request field and intent schema validation
+	_, span := tracing.Start(c.Request.Context(), "ValidateIntent", attribute.String("uuid", req.UUID), attribute.String("intent.type", req.Intent.Type))
+	fieldErrors := services.ValidateRequestFields(req)
+	span.SetAttributes(attribute.Int("field_errors", len(fieldErrors)))
+	span.End()
+	if len(fieldErrors) > 0 {
+		LogInfo(middleware.Logger(c), "ValidateUserActionHandler", "action", "Rejected by schema validation", "details", fieldErrors)
+		apiErr := ErrInvalidRequest
+		if strings.HasPrefix(fieldErrors[0].Field, "intent") {
+			apiErr = ErrInvalidIntent
+		}
+		ValidateFieldErrorResponse(c, apiErr.WithMessage(fieldErrors[0].Field+": "+fieldErrors[0].Reason), fieldErrors)
+		return
+	}
+
+	// Exchange rate validation
+	if err := services.ValidateConversion(req.Intent); err != nil {
+		var mismatch *conversion.Mismatch
+		if errors.As(err, &mismatch) {
+			LogInfo(middleware.Logger(c), "ValidateUserActionHandler", "action", "Rejected by conversion table", "code", mismatch.Code, "reason", mismatch.Message)
+			ValidateErrorResponse(c, badRequest(mismatch.Code, mismatch.Message))
+			return
+		}
+		ValidateErrorResponse(c, ErrInvalidIntent.WithMessage(err.Error()))
 		return
 	}
 
@@ -36,44 +68,147 @@ func ValidateUserActionHandler(c *gin.Context) {
 		return
 	}
 
-	// Store UUID and SessionID mapping
-	err := database.StoreUUIDMapping(req.UUID, sessionID)
+	// Load player (unknown players are rejected before anything is stored)
+	if _, err := database.GetOrCreateSessionAssets(sessionID); err != nil {
+		LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Failed to load player")
+		ValidateErrorResponse(c, LookupError(err, ErrDBError))
+		return
+	}
+
+	// Enrolled wallet check
+	if err := services.CheckEnrolledWallet(sessionID, req.UserAddress); err != nil {
+		LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Wallet enrollment check failed", "userAddress", req.UserAddress)
+		ValidateErrorResponse(c, LookupError(err, ErrDBError))
+		return
+	}
+
+	// Release pending credits that never received a result so they stop counting toward limits
+	if err := services.ExpireReservations(sessionID, time.Now()); err != nil {
+		LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Failed to expire reservations")
+	}
+
+	// Each order UUID is validated only once
+	existing, err := database.GetOrder(req.UUID)
 	if err != nil {
-		LogError(slog.Default(), "ValidateUserActionHandler", err, "action", "Failed to store UUID mapping")
-		ValidateErrorResponse(c, http.StatusInternalServerError, ErrorCodeUUIDMappingFailed)
+		LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Failed to get order")
+		ValidateErrorResponse(c, ErrDBError)
+		return
+	}
+	if existing != nil {
+		LogInfo(middleware.Logger(c), "ValidateUserActionHandler", "action", "Rejected duplicate order", "status", existing.Status)
+		ValidateErrorResponse(c, ErrDuplicateUUID)
 		return
 	}
 
-	requestBytes, _ := json.Marshal(req)
-	LogInfo(slog.Default(), "ValidateUserActionHandler", "sessionID", sessionID, "uuid", req.UUID, "req", string(requestBytes))
+	// Business rules (limits, caps, cooldowns)
+	if err := services.ReserveRuleUsage(req.ProjectID, sessionID, req.UUID, req.Intent); err != nil {
+		var violation *rules.Violation
+		if errors.As(err, &violation) {
+			LogInfo(middleware.Logger(c), "ValidateUserActionHandler", "action", "Rejected by business rule", "code", violation.Code, "reason", violation.Message)
+			ValidateErrorResponse(c, badRequest(violation.Code, violation.Message))
+			return
+		}
+		if errors.Is(err, database.ErrOrderExists) {
+			// Another request with the same UUID holds the usage; it is left untouched
+			LogInfo(middleware.Logger(c), "ValidateUserActionHandler", "action", "Rejected duplicate order", "reason", err.Error())
+			ValidateErrorResponse(c, ErrDuplicateUUID)
+			return
+		}
+		LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Failed to evaluate business rules")
+		ValidateErrorResponse(c, ErrDBError)
+		return
+	}
 
 	// For mint method, validate and deduct assets
-	if req.Intent.Type == "assemble" {
-		if err := services.ValidateAndProcessMint(sessionID, req.Intent.From); err != nil {
-			ValidateErrorResponse(c, http.StatusBadRequest, ErrorCodeInsufficientBalance)
+	var deducted []models.PairAsset
+	if req.Intent.Type == models.IntentTypeAssemble {
+		if err := services.ValidateAndProcessMint(c.Request.Context(), sessionID, req.UUID, req.Intent.From); err != nil {
+			apiErr := LookupError(err, ErrDBError)
+			LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Failed to deduct assets", "code", apiErr.Code)
+			services.ReleaseRuleUsage(req.UUID)
+			ValidateErrorResponse(c, apiErr)
+			return
+		}
+		deducted = req.Intent.From
+	}
+
+	// Store order (UUID and SessionID mapping with status and deducted assets)
+	err = database.StoreOrder(&database.UUIDMapping{
+		UUID:        req.UUID,
+		SessionID:   sessionID,
+		ProjectID:   req.ProjectID,
+		IntentType:  req.Intent.Type,
+		UserAddress: req.UserAddress,
+		Intent:      &req.Intent,
+		Deducted:    deducted,
+	})
+	if err != nil {
+		LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Failed to store UUID mapping")
+		if len(deducted) > 0 {
+			if refundErr := database.AddAssets(sessionID, deducted, database.LedgerRef{Source: database.LedgerSourceRefund, Reference: req.UUID, Reason: "failed to store order"}); refundErr != nil {
+				LogError(middleware.Logger(c), "ValidateUserActionHandler", refundErr, "action", "Failed to refund deducted assets")
+			}
+		}
+		// The usage was recorded by this request (StoreOrderUsage never replaces a row), so it is released
+		// even when the order belongs to another request
+		services.ReleaseRuleUsage(req.UUID)
+		if errors.Is(err, database.ErrOrderExists) {
+			ValidateErrorResponse(c, ErrDuplicateUUID)
+			return
+		}
+		ValidateErrorResponse(c, ErrUUIDMappingFailed)
+		return
+	}
+
+	// For disassemble, reserve the credit until the result webhook arrives
+	if req.Intent.Type == models.IntentTypeDisassemble {
+		if err := services.ReserveCredit(req.ProjectID, sessionID, req.UUID, req.Intent); err != nil {
+			LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Failed to reserve credit")
+			services.RejectOrder(req.UUID, "failed to reserve credit")
+			ValidateErrorResponse(c, ErrDBError)
 			return
 		}
 	}
 
+	requestBytes, _ := json.Marshal(req)
+	LogInfo(middleware.Logger(c), "ValidateUserActionHandler", "req", string(requestBytes))
+
 	// Generate validator signature (in actual implementation, use validator's private key)
 	userSigBytes := hexutil.MustDecode(req.UserSig)
 	digestHash := common.HexToHash(req.Digest)
-	validatorSig, err := services.GenerateValidatorSignature(userSigBytes, digestHash)
+	validatorSig, err := services.GenerateValidatorSignature(c.Request.Context(), userSigBytes, digestHash)
+	if err != nil {
+		LogError(middleware.Logger(c), "GenerateValidatorSignature", err)
+		services.RejectOrder(req.UUID, "failed to generate validator signature")
+		ValidateErrorResponse(c, ErrSignatureGeneration)
+		return
+	}
+
+	// Record the signature in the audit log before it is released
+	err = services.AuditSignature(audit.Record{
+		Digest:       req.Digest,
+		ValidatorSig: validatorSig.String(),
+		UserAddress:  req.UserAddress,
+		SessionID:    sessionID,
+		ProjectID:    req.ProjectID,
+		UUID:         req.UUID,
+		Intent:       &req.Intent,
+		// Empty unless the request arrived over mutual TLS
+		ClientIdentity: middleware.ClientIdentity(c),
+	})
 	if err != nil {
-		LogError(slog.Default(), "GenerateValidatorSignature", err)
-		ValidateErrorResponse(c, http.StatusInternalServerError, ErrorCodeSignatureGeneration)
+		LogError(middleware.Logger(c), "ValidateUserActionHandler", err, "action", "Failed to audit validator signature")
+		services.RejectOrder(req.UUID, "failed to audit validator signature")
+		ValidateErrorResponse(c, ErrSignatureGeneration)
 		return
 	}
 
-	LogInfo(slog.Default(), "validateUserActionHandler", "validatorSig", validatorSig, "userSig", req.UserSig, "digest", req.Digest)
+	LogInfo(middleware.Logger(c), "validateUserActionHandler", "validatorSig", validatorSig, "userSig", req.UserSig, "digest", req.Digest)
 
 	// Success response
 	response := models.ValidateResponse{
 		Success: true,
-		Data: struct {
-			UserSig      string `json:"userSig"`
-			ValidatorSig string `json:"validatorSig"`
-		}{
+		Data: &models.ValidateData{
 			UserSig:      req.UserSig,
 			ValidatorSig: validatorSig.String(),
 		},
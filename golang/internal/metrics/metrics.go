@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace prefix of every metric
+const namespace = "ramp"
+
+// Context keys for request labels set by handlers
+const (
+	projectKey    = "metrics.project"
+	intentTypeKey = "metrics.intentType"
+	methodKey     = "metrics.method"
+	errorCodeKey  = "metrics.errorCode"
+)
+
+// codeOK error code label of successful requests
+const codeOK = "OK"
+
+// requestLabels labels of request metrics
+var requestLabels = []string{"endpoint", "project", "intent_type", "method", "code"}
+
+// Collectors
+var (
+	// Requests API requests by endpoint, project, intent type, method and error code
+	Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "API requests by endpoint, project, intent type, method and error code.",
+	}, requestLabels)
+
+	// RequestDuration API request latency
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "API request latency by endpoint, project, intent type, method and error code.",
+		Buckets:   prometheus.DefBuckets,
+	}, requestLabels)
+
+	// HMACFailures requests rejected by X-HMAC-SIGNATURE verification
+	HMACFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hmac_failures_total",
+		Help:      "Requests rejected by X-HMAC-SIGNATURE verification.",
+	}, []string{"endpoint"})
+
+	// RateLimited requests throttled by the rate limiter
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Requests throttled by endpoint and rate limit scope (ip, session or project).",
+	}, []string{"endpoint", "scope"})
+
+	// SessionsEvicted stored sessions removed by idle timeout or to make room for new sessions
+	SessionsEvicted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sessions_evicted_total",
+		Help:      "Stored sessions evicted by reason (idle or capacity).",
+	}, []string{"reason"})
+
+	// SignerDuration validator signature latency
+	SignerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "signer_duration_seconds",
+		Help:      "Validator signature latency by result.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"result"})
+
+	// AssetsDeducted in-game asset amounts deducted from players
+	AssetsDeducted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "assets_deducted_total",
+		Help:      "In-game asset amounts deducted from players.",
+	}, []string{"asset"})
+
+	// AssetsCredited in-game asset amounts credited to players (results and refunds)
+	AssetsCredited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "assets_credited_total",
+		Help:      "In-game asset amounts credited to players, including refunds.",
+	}, []string{"asset"})
+)
+
+func init() {
+	prometheus.MustRegister(Requests, RequestDuration, HMACFailures, RateLimited, SessionsEvicted, SignerDuration, AssetsDeducted, AssetsCredited)
+}
+
+// SetLabels set the project, intent type and method labels of the current request
+func SetLabels(c *gin.Context, projectID, intentType, method string) {
+	c.Set(projectKey, projectID)
+	c.Set(intentTypeKey, intentType)
+	c.Set(methodKey, method)
+}
+
+// SetErrorCode set the error code label of the current request
+func SetErrorCode(c *gin.Context, code string) {
+	c.Set(errorCodeKey, code)
+}
+
+// Middleware record request count and latency once the handler has finished
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			// Unmatched routes are not recorded to keep label cardinality bounded
+			return
+		}
+		code := c.GetString(errorCodeKey)
+		if code == "" {
+			code = codeOK
+			if c.Writer.Status() >= 400 {
+				code = strconv.Itoa(c.Writer.Status())
+			}
+		}
+
+		labels := prometheus.Labels{
+			"endpoint":    endpoint,
+			"project":     c.GetString(projectKey),
+			"intent_type": c.GetString(intentTypeKey),
+			"method":      c.GetString(methodKey),
+			"code":        code,
+		}
+		Requests.With(labels).Inc()
+		RequestDuration.With(labels).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveSigner record validator signature latency
+func ObserveSigner(start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	SignerDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
+
+// AddAmount add asset amounts to a deducted or credited counter
+func AddAmount(counter *prometheus.CounterVec, assetID string, amount uint64) {
+	counter.WithLabelValues(assetID).Add(float64(amount))
+}
+
+// pendingCollector pending order counts read from the database at scrape time
+type pendingCollector struct {
+	orders       func() (map[string]int, error)
+	reservations func() (int, error)
+	ordersDesc   *prometheus.Desc
+	creditsDesc  *prometheus.Desc
+}
+
+// RegisterPendingOrders export validated orders waiting for a result (by intent type) and pending credits
+func RegisterPendingOrders(orders func() (map[string]int, error), reservations func() (int, error)) {
+	prometheus.MustRegister(&pendingCollector{
+		orders:       orders,
+		reservations: reservations,
+		ordersDesc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "pending_orders"),
+			"Validated orders waiting for their result webhook by intent type.", []string{"intent_type"}, nil),
+		creditsDesc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "pending_credits"),
+			"Disassemble credits reserved until their result webhook.", nil, nil),
+	})
+}
+
+func (p *pendingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.ordersDesc
+	ch <- p.creditsDesc
+}
+
+func (p *pendingCollector) Collect(ch chan<- prometheus.Metric) {
+	if counts, err := p.orders(); err != nil {
+		slog.Warn("metrics", "warning", "Failed to count pending orders", "err", err)
+	} else {
+		for intentType, count := range counts {
+			ch <- prometheus.MustNewConstMetric(p.ordersDesc, prometheus.GaugeValue, float64(count), intentType)
+		}
+	}
+
+	if count, err := p.reservations(); err != nil {
+		slog.Warn("metrics", "warning", "Failed to count pending credits", "err", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(p.creditsDesc, prometheus.GaugeValue, float64(count))
+	}
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.POST("/api/validate", func(c *gin.Context) {
+		SetLabels(c, "metrics-project", "assemble", "mint")
+		if c.Query("fail") != "" {
+			SetErrorCode(c, "INSUFFICIENT_BALANCE")
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/validate", "/api/validate?fail=1", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+	}
+
+	// 성공과 오류 코드별로 집계, 등록되지 않은 경로는 제외
+	assert.Equal(t, 1.0, testutil.ToFloat64(Requests.WithLabelValues("/api/validate", "metrics-project", "assemble", "mint", codeOK)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(Requests.WithLabelValues("/api/validate", "metrics-project", "assemble", "mint", "INSUFFICIENT_BALANCE")))
+	assert.Equal(t, 2, testutil.CollectAndCount(RequestDuration, "ramp_request_duration_seconds"))
+}
+
+func TestPendingCollector(t *testing.T) {
+	collector := &pendingCollector{
+		orders:       func() (map[string]int, error) { return map[string]int{"assemble": 2, "disassemble": 1}, nil },
+		reservations: func() (int, error) { return 0, errors.New("database not initialized") },
+		ordersDesc:   prometheus.NewDesc("ramp_pending_orders", "help", []string{"intent_type"}, nil),
+		creditsDesc:  prometheus.NewDesc("ramp_pending_credits", "help", nil, nil),
+	}
+
+	// 조회에 실패한 값은 내보내지 않음
+	expected := `
+# HELP ramp_pending_orders help
+# TYPE ramp_pending_orders gauge
+ramp_pending_orders{intent_type="assemble"} 2
+ramp_pending_orders{intent_type="disassemble"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"sync"
+
+	"sample-game-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// operatorKey context key of the authenticated operator
+const operatorKey = "admin.operator"
+
+// adminKey API key of one operator (only the hash is kept)
+type adminKey struct {
+	operator string
+	hash     [sha256.Size]byte
+}
+
+var (
+	// adminKeys operator API keys (empty disables the admin API)
+	adminKeys []adminKey
+	adminMu   sync.RWMutex
+)
+
+// InitAdminAuth configure operator API keys ("operator:key" pairs separated by commas)
+func InitAdminAuth(cfg config.AdminConfig) error {
+	var keys []adminKey
+	for i, pair := range strings.Split(cfg.APIKeys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		operator, key, ok := strings.Cut(pair, ":")
+		operator, key = strings.TrimSpace(operator), strings.TrimSpace(key)
+		if !ok || operator == "" || key == "" {
+			// The entry itself is not echoed since it may hold a key
+			return fmt.Errorf("admin API key %d must be operator:key", i+1)
+		}
+		keys = append(keys, adminKey{operator: operator, hash: sha256.Sum256([]byte(key))})
+	}
+
+	adminMu.Lock()
+	defer adminMu.Unlock()
+	adminKeys = keys
+	return nil
+}
+
+// AdminAuthMiddleware authenticate operators by "Authorization: Bearer <key>"
+// Every request is rejected while no key is configured; onUnauthorized answers and aborts rejected requests
+func AdminAuthMiddleware(onUnauthorized gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		hash := sha256.Sum256([]byte(strings.TrimSpace(token)))
+
+		adminMu.RLock()
+		keys := adminKeys
+		adminMu.RUnlock()
+
+		// Compare against every key so the match position is not observable
+		operator := ""
+		for _, key := range keys {
+			if subtle.ConstantTimeCompare(hash[:], key.hash[:]) == 1 && ok {
+				operator = key.operator
+			}
+		}
+		if operator == "" {
+			Logger(c).Warn("AdminAuthMiddleware", "FullPath", c.FullPath(), "warning", "Invalid admin API key")
+			onUnauthorized(c)
+			return
+		}
+
+		c.Set(operatorKey, operator)
+		AddLogFields(c, "operator", operator)
+		c.Next()
+	}
+}
+
+// Operator authenticated operator of the current admin request
+func Operator(c *gin.Context) string {
+	return c.GetString(operatorKey)
+}
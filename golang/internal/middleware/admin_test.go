@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sample-game-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin", AdminAuthMiddleware(func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}), func(c *gin.Context) {
+		c.String(http.StatusOK, Operator(c))
+	})
+
+	get := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 키가 없으면 모든 요청 거부
+	require.NoError(t, InitAdminAuth(config.AdminConfig{}))
+	assert.Equal(t, http.StatusUnauthorized, get("Bearer ").Code)
+
+	require.NoError(t, InitAdminAuth(config.AdminConfig{APIKeys: "alice:alice-key, bob:bob-key"}))
+	defer InitAdminAuth(config.AdminConfig{})
+
+	// 키에 해당하는 운영자로 인증
+	w := get("Bearer bob-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bob", w.Body.String())
+
+	for _, authorization := range []string{"", "Bearer wrong-key", "alice-key", "Basic alice-key"} {
+		assert.Equal(t, http.StatusUnauthorized, get(authorization).Code, authorization)
+	}
+
+	// 형식이 잘못된 설정은 오류
+	assert.Error(t, InitAdminAuth(config.AdminConfig{APIKeys: "secret-without-operator"}))
+}
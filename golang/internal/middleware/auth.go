@@ -1,23 +1,31 @@
 package middleware
 
 import (
-	"log/slog"
 	"net/http"
 
+	"sample-game-backend/internal/tracing"
+
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // AuthMiddleware authentication middleware
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Spans started by later handlers are children of this span
+		ctx, span := tracing.Start(c.Request.Context(), "AuthMiddleware")
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
 		authHeader := c.GetHeader("Authorization")
 		dappAuth := c.GetHeader("X-Dapp-Authorization")
 		sessionID := c.GetHeader("X-Dapp-SessionID")
 
-		slog.Info("AuthMiddleware", "FullPath", c.FullPath(), "authHeader", authHeader, "dappAuth", dappAuth, "sessionID", sessionID)
+		AddLogFields(c, "sessionID", sessionID)
+		Logger(c).Info("AuthMiddleware", "FullPath", c.FullPath(), "authHeader", authHeader, "dappAuth", dappAuth)
 		c.Set("Authorization", authHeader)
 		c.Set("X-Dapp-Authorization", dappAuth)
 		c.Set("X-Dapp-SessionID", sessionID)
+		span.SetAttributes(attribute.Bool("session_id.present", sessionID != ""))
 		c.Next()
 	}
 }
@@ -27,7 +35,8 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Authorization, X-Dapp-Authorization, X-Dapp-SessionID, Content-Type")
+		c.Header("Access-Control-Allow-Headers", "Authorization, X-Dapp-Authorization, X-Dapp-SessionID, Content-Type, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", RequestIDHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
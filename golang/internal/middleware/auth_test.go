@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sample-game-backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAuthMiddlewareSpanContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/validate", AuthMiddleware(), func(c *gin.Context) {
+		_, span := tracing.Start(c.Request.Context(), "handler")
+		span.End()
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/validate", nil)
+	req.Header.Set("X-Dapp-SessionID", "auth-span")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// 핸들러 스팬은 AuthMiddleware 스팬의 자식
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "handler", spans[0].Name())
+	assert.Equal(t, "AuthMiddleware", spans[1].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
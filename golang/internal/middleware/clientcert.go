@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"crypto/x509"
+	"strings"
+	"sync"
+
+	"sample-game-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientIdentityKey context key of the verified client certificate name
+const clientIdentityKey = "tls.clientIdentity"
+
+var (
+	// clientCertRequired reject requests without a verified client certificate
+	clientCertRequired bool
+	// clientNames accepted certificate names (empty accepts any verified certificate)
+	clientNames map[string]bool
+	clientMu    sync.RWMutex
+)
+
+// InitClientCert configure mutual TLS of CROSS RAMP requests (required when a client CA is configured)
+func InitClientCert(cfg config.TLSConfig) {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(cfg.ClientNames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names[strings.ToLower(name)] = true
+		}
+	}
+
+	clientMu.Lock()
+	defer clientMu.Unlock()
+	clientCertRequired = cfg.ClientCAFile != ""
+	clientNames = names
+}
+
+// ClientCertMiddleware require a verified client certificate with an accepted name
+// Requests pass unchecked while mutual TLS is off; onUnauthorized answers and aborts rejected requests
+func ClientCertMiddleware(onUnauthorized gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientMu.RLock()
+		required, names := clientCertRequired, clientNames
+		clientMu.RUnlock()
+		if !required {
+			c.Next()
+			return
+		}
+
+		// Chains are only present when the handshake verified the certificate against the client CA
+		if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 {
+			Logger(c).Warn("ClientCertMiddleware", "FullPath", c.FullPath(), "warning", "No verified client certificate")
+			onUnauthorized(c)
+			return
+		}
+		leaf := c.Request.TLS.VerifiedChains[0][0]
+		identity, ok := acceptedName(leaf, names)
+		if !ok {
+			Logger(c).Warn("ClientCertMiddleware", "FullPath", c.FullPath(), "warning", "Client certificate name not accepted", "subject", leaf.Subject.String())
+			onUnauthorized(c)
+			return
+		}
+
+		c.Set(clientIdentityKey, identity)
+		AddLogFields(c, "clientIdentity", identity)
+		c.Next()
+	}
+}
+
+// acceptedName first certificate name (common name, then DNS SANs) accepted by names
+// Without configured names the certificate is identified by its first name or its subject.
+func acceptedName(cert *x509.Certificate, names map[string]bool) (string, bool) {
+	for _, name := range append([]string{cert.Subject.CommonName}, cert.DNSNames...) {
+		if name != "" && (len(names) == 0 || names[strings.ToLower(name)]) {
+			return name, true
+		}
+	}
+	if len(names) == 0 {
+		return cert.Subject.String(), true
+	}
+	return "", false
+}
+
+// ClientIdentity verified client certificate name of the current request (empty without mutual TLS)
+func ClientIdentity(c *gin.Context) string {
+	return c.GetString(clientIdentityKey)
+}
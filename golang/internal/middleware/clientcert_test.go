@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sample-game-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCertMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/result", ClientCertMiddleware(func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}), func(c *gin.Context) {
+		c.String(http.StatusOK, ClientIdentity(c))
+	})
+
+	// 핸드셰이크에서 검증된 체인을 흉내냄
+	post := func(cert *x509.Certificate) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/result", nil)
+		if cert != nil {
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	ramp := &x509.Certificate{Subject: pkix.Name{CommonName: "ramp-client"}, DNSNames: []string{"webhook.crosstoken.io"}}
+	other := &x509.Certificate{Subject: pkix.Name{CommonName: "other-client"}}
+
+	// 상호 TLS가 꺼져 있으면 통과
+	InitClientCert(config.TLSConfig{})
+	assert.Equal(t, http.StatusOK, post(nil).Code)
+
+	// 이름 제한이 없으면 검증된 인증서는 모두 허용
+	InitClientCert(config.TLSConfig{ClientCAFile: "ca.pem"})
+	defer InitClientCert(config.TLSConfig{})
+	assert.Equal(t, http.StatusUnauthorized, post(nil).Code)
+	assert.Equal(t, "other-client", post(other).Body.String())
+
+	// 허용된 이름(CN 또는 DNS SAN)만 통과
+	InitClientCert(config.TLSConfig{ClientCAFile: "ca.pem", ClientNames: "Webhook.CrossToken.io"})
+	w := post(ramp)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "webhook.crosstoken.io", w.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, post(other).Code)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sample-game-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hmacRouter 서명 검증 후 본문을 그대로 돌려주는 테스트 라우터
+func hmacRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/result", HMACMiddleware(func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errorCode": "INVALID_MESSAGE"})
+	}), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return r
+}
+
+func postSigned(r *gin.Engine, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(HMACHeader, signature)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHMACMiddleware(t *testing.T) {
+	require.NoError(t, InitHMAC(config.HMACConfig{Key: "c2VjcmV0LWhtYWMta2V5"}))
+	defer InitHMAC(config.HMACConfig{})
+
+	r := hmacRouter()
+	body := `{"uuid":"hmac-test"}`
+	signature := SignBody([]byte(body))
+	require.NotEmpty(t, signature)
+
+	// 올바른 서명은 통과하고 핸들러가 본문을 다시 읽을 수 있음
+	w := postSigned(r, body, signature)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, http.StatusOK, postSigned(r, body, strings.ToUpper(signature)).Code)
+
+	// 서명 누락 또는 본문 변조
+	assert.Equal(t, http.StatusBadRequest, postSigned(r, body, "").Code)
+	w = postSigned(r, `{"uuid":"tampered"}`, signature)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_MESSAGE")
+}
+
+func TestHMACMiddlewareDisabled(t *testing.T) {
+	require.NoError(t, InitHMAC(config.HMACConfig{}))
+	assert.Equal(t, http.StatusOK, postSigned(hmacRouter(), "{}", "").Code)
+
+	// 잘못된 키는 설정 오류
+	assert.Error(t, InitHMAC(config.HMACConfig{Key: "not base64!"}))
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// Rate limit scopes (also the "scope" label of throttled request metrics)
+const (
+	RateLimitScopeIP      = "ip"
+	RateLimitScopeSession = "session"
+	RateLimitScopeProject = "project"
+)
+
+// bucket token bucket of one key
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// keyedLimiter token buckets of one scope, one per key
+// Buckets unused for ttl are dropped so arbitrary keys do not grow memory without bound.
+type keyedLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	ttl       time.Duration
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func newKeyedLimiter(limit config.RateLimit, ttl time.Duration) *keyedLimiter {
+	if limit.Rate <= 0 || limit.Burst == 0 {
+		return nil
+	}
+	return &keyedLimiter{
+		limit:   rate.Limit(limit.Rate),
+		burst:   int(min(limit.Burst, math.MaxInt32)),
+		ttl:     ttl,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// reserve take a token for key; the reservation is cancelled when the request is rejected by another scope
+func (l *keyedLimiter) reserve(key string, now time.Time) *rate.Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ttl > 0 && now.Sub(l.lastSweep) >= l.ttl {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) >= l.ttl {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	return b.limiter.ReserveN(now, 1)
+}
+
+var (
+	// rateLimiters limiters by scope (nil entries are not limited)
+	rateLimiters map[string]*keyedLimiter
+	// webhookIPLimit whether the webhook routes are also limited by client IP
+	webhookIPLimit bool
+	rateLimitMu    sync.RWMutex
+)
+
+// InitRateLimit configure the token buckets of the public API
+func InitRateLimit(cfg config.RateLimitConfig) {
+	limiters := map[string]*keyedLimiter{
+		RateLimitScopeIP:      newKeyedLimiter(cfg.IP, cfg.IdleTTL),
+		RateLimitScopeSession: newKeyedLimiter(cfg.Session, cfg.IdleTTL),
+		RateLimitScopeProject: newKeyedLimiter(cfg.Project, cfg.IdleTTL),
+	}
+
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	rateLimiters = limiters
+	webhookIPLimit = cfg.WebhookIP
+}
+
+// RateLimitMiddleware throttle requests by client IP, X-Dapp-SessionID and the body's project_id
+// Requests pass unchecked while no limit is configured; onLimited answers and aborts throttled requests
+func RateLimitMiddleware(onLimited gin.HandlerFunc) gin.HandlerFunc {
+	return rateLimit(onLimited, false)
+}
+
+// WebhookRateLimitMiddleware throttle CROSS RAMP webhooks by X-Dapp-SessionID and the body's project_id
+// They come from the CROSS RAMP backend, so the client IP scope only applies when enabled by configuration.
+func WebhookRateLimitMiddleware(onLimited gin.HandlerFunc) gin.HandlerFunc {
+	return rateLimit(onLimited, true)
+}
+
+// rateLimit take a token from every enabled scope, or none when any scope rejects the request
+func rateLimit(onLimited gin.HandlerFunc, webhook bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rateLimitMu.RLock()
+		limiters, ipLimit := rateLimiters, !webhook || webhookIPLimit
+		rateLimitMu.RUnlock()
+
+		now := time.Now()
+		var reserved []*rate.Reservation
+		for _, scope := range []string{RateLimitScopeIP, RateLimitScopeSession, RateLimitScopeProject} {
+			limiter := limiters[scope]
+			if limiter == nil || (scope == RateLimitScopeIP && !ipLimit) {
+				continue
+			}
+			key := rateLimitKey(c, scope)
+			if key == "" {
+				continue
+			}
+			reservation := limiter.reserve(key, now)
+			reserved = append(reserved, reservation)
+			if retryAfter := reservation.DelayFrom(now); retryAfter > 0 {
+				// Give back the tokens taken from the other scopes
+				for _, r := range reserved {
+					r.CancelAt(now)
+				}
+				Logger(c).Warn("RateLimitMiddleware", "FullPath", c.FullPath(), "warning", "Rate limit exceeded", "scope", scope, "retryAfter", retryAfter)
+				metrics.RateLimited.WithLabelValues(c.FullPath(), scope).Inc()
+				c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				onLimited(c)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey key of the request in scope (empty when the request carries none)
+func rateLimitKey(c *gin.Context, scope string) string {
+	switch scope {
+	case RateLimitScopeIP:
+		return c.ClientIP()
+	case RateLimitScopeSession:
+		return c.GetHeader("X-Dapp-SessionID")
+	case RateLimitScopeProject:
+		return peekProjectID(c)
+	}
+	return ""
+}
+
+// maxPeekBytes largest request body read for its project_id
+const maxPeekBytes = 1 << 20
+
+// peekProjectID project_id of a JSON request body, leaving the body readable by the handler
+// Bodies larger than maxPeekBytes are not parsed and carry no project key.
+func peekProjectID(c *gin.Context) string {
+	if c.Request.Method != http.MethodPost || c.Request.Body == nil {
+		return ""
+	}
+	original := c.Request.Body
+	body, err := io.ReadAll(io.LimitReader(original, maxPeekBytes+1))
+	c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), original), Closer: original}
+	if err != nil || len(body) > maxPeekBytes {
+		return ""
+	}
+
+	var req struct {
+		ProjectID string `json:"project_id"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return ""
+	}
+	return req.ProjectID
+}
+
+// readCloser body replayed from the peeked bytes, closing the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
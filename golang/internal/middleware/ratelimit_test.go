@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/validate", RateLimitMiddleware(func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusTooManyRequests)
+	}), func(c *gin.Context) {
+		// 핸들러는 여전히 본문을 읽을 수 있어야 함
+		var req struct {
+			ProjectID string `json:"project_id"`
+		}
+		require.NoError(t, c.ShouldBindJSON(&req))
+		c.String(http.StatusOK, req.ProjectID)
+	})
+
+	post := func(ip, sessionID, projectID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(`{"project_id":"`+projectID+`"}`))
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Dapp-SessionID", sessionID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 설정 전에는 제한 없음
+	InitRateLimit(config.RateLimitConfig{})
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, post("10.0.0.1", "session-a", "project-a").Code)
+	}
+
+	// 충전 속도를 매우 느리게 두어 버스트만 허용
+	InitRateLimit(config.RateLimitConfig{
+		Session: config.RateLimit{Rate: 0.001, Burst: 2},
+		IP:      config.RateLimit{Rate: 0.001, Burst: 3},
+		Project: config.RateLimit{Rate: 0.001, Burst: 4},
+		IdleTTL: time.Minute,
+	})
+	defer InitRateLimit(config.RateLimitConfig{})
+
+	// 세션별 버스트 초과
+	w := post("10.0.0.1", "session-a", "project-a")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "project-a", w.Body.String())
+	assert.Equal(t, http.StatusOK, post("10.0.0.1", "session-a", "project-a").Code)
+	w = post("10.0.0.1", "session-a", "project-a")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// 거부된 요청은 IP와 프로젝트 토큰을 쓰지 않으므로 같은 IP의 다른 세션은 한 번 더 허용
+	assert.Equal(t, http.StatusOK, post("10.0.0.1", "session-b", "project-a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, post("10.0.0.1", "session-b", "project-a").Code)
+
+	// 프로젝트 버스트는 IP와 세션이 달라도 공유
+	assert.Equal(t, http.StatusOK, post("10.0.0.2", "session-c", "project-a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, post("10.0.0.3", "session-d", "project-a").Code)
+	assert.Equal(t, http.StatusOK, post("10.0.0.3", "session-d", "project-b").Code)
+}
+
+func TestWebhookRateLimitSkipsIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/validate", WebhookRateLimitMiddleware(func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusTooManyRequests)
+	}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	post := func(sessionID string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(`{}`))
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Dapp-SessionID", sessionID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	limits := config.RateLimitConfig{
+		Session: config.RateLimit{Rate: 0.001, Burst: 1},
+		IP:      config.RateLimit{Rate: 0.001, Burst: 2},
+	}
+	defer InitRateLimit(config.RateLimitConfig{})
+
+	// CROSS RAMP 백엔드의 IP 하나로 여러 세션의 웹훅이 와도 IP로는 제한하지 않음
+	InitRateLimit(limits)
+	for _, sessionID := range []string{"session-a", "session-b", "session-c", "session-d"} {
+		assert.Equal(t, http.StatusOK, post(sessionID))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, post("session-a"))
+
+	// 설정으로 켠 경우에만 IP 제한
+	limits.WebhookIP = true
+	InitRateLimit(limits)
+	assert.Equal(t, http.StatusOK, post("session-a"))
+	assert.Equal(t, http.StatusOK, post("session-b"))
+	assert.Equal(t, http.StatusTooManyRequests, post("session-c"))
+}
+
+func TestKeyedLimiterDropsIdleBuckets(t *testing.T) {
+	limiter := newKeyedLimiter(config.RateLimit{Rate: 1, Burst: 1}, time.Minute)
+	now := time.Now()
+
+	assert.Zero(t, limiter.reserve("a", now).DelayFrom(now))
+	assert.Equal(t, time.Second, limiter.reserve("a", now).DelayFrom(now))
+
+	// 유휴 시간이 지나면 버킷 제거
+	later := now.Add(2 * time.Minute)
+	assert.Zero(t, limiter.reserve("b", later).DelayFrom(later))
+	assert.NotContains(t, limiter.buckets, "a")
+	assert.Contains(t, limiter.buckets, "b")
+}
+
+func TestRateLimitUntrustedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(nil))
+	r.POST("/api/validate", RateLimitMiddleware(func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusTooManyRequests)
+	}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	InitRateLimit(config.RateLimitConfig{IP: config.RateLimit{Rate: 0.001, Burst: 1}, IdleTTL: time.Minute})
+	defer InitRateLimit(config.RateLimitConfig{})
+
+	// 신뢰하지 않는 프록시의 X-Forwarded-For는 IP 키로 쓰지 않음
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(`{}`))
+		req.RemoteAddr = "10.0.1.1:1234"
+		req.Header.Set("X-Forwarded-For", "192.0.2."+strconv.Itoa(i+1))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code)
+	}
+}
+
+func TestPeekProjectIDLargeBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"project_id":"project-a","padding":"` + strings.Repeat("x", maxPeekBytes) + `"}`
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(body))
+
+	// 제한보다 큰 본문은 파싱하지 않지만 핸들러는 전체 본문을 읽을 수 있음
+	assert.Empty(t, peekProjectID(c))
+	read, err := io.ReadAll(c.Request.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(read))
+}
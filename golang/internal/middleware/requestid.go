@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"log/slog"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader header carrying the request ID (honored when sent, always returned)
+const RequestIDHeader = "X-Request-ID"
+
+// Context keys of the request ID and the request-scoped logger
+const (
+	requestIDKey = "requestID"
+	loggerKey    = "logger"
+)
+
+// requestIDPattern incoming request IDs accepted as is (anything else is replaced)
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware assign the request ID and a logger carrying it to the request
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Set(loggerKey, slog.Default().With("requestID", requestID))
+		c.Header(RequestIDHeader, requestID)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request.id", requestID))
+		c.Next()
+	}
+}
+
+// RequestID request ID of the current request (empty outside RequestIDMiddleware)
+func RequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// Logger request-scoped logger of the current request (slog.Default() outside RequestIDMiddleware)
+func Logger(c *gin.Context) *slog.Logger {
+	if value, exists := c.Get(loggerKey); exists {
+		if logger, ok := value.(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// AddLogFields add fields (session ID, project ID, order UUID, ...) to every later log of the current request
+func AddLogFields(c *gin.Context, args ...any) {
+	c.Set(loggerKey, Logger(c).With(args...))
+}
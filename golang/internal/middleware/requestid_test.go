@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/id", func(c *gin.Context) {
+		c.String(http.StatusOK, RequestID(c))
+	})
+
+	get := func(requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/id", nil)
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 전달된 ID는 그대로 사용
+	w := get("ramp-7f3c2a")
+	assert.Equal(t, "ramp-7f3c2a", w.Header().Get(RequestIDHeader))
+	assert.Equal(t, "ramp-7f3c2a", w.Body.String())
+
+	// 없거나 형식이 잘못된 ID는 새로 생성
+	for _, requestID := range []string{"", "bad id\n", strings.Repeat("a", 129)} {
+		w := get(requestID)
+		_, err := uuid.Parse(w.Header().Get(RequestIDHeader))
+		assert.NoError(t, err, requestID)
+		assert.Equal(t, w.Header().Get(RequestIDHeader), w.Body.String())
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), AuthMiddleware())
+	r.POST("/validate", func(c *gin.Context) {
+		AddLogFields(c, "projectID", "project-1", "uuid", "order-1")
+		Logger(c).Info("handler")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/validate", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	req.Header.Set("X-Dapp-SessionID", "session-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// 요청 로그에 요청 ID, 세션, 프로젝트, 주문 UUID가 포함됨
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &entry))
+	assert.Equal(t, "handler", entry["msg"])
+	assert.Equal(t, "req-1", entry["requestID"])
+	assert.Equal(t, "session-1", entry["sessionID"])
+	assert.Equal(t, "project-1", entry["projectID"])
+	assert.Equal(t, "order-1", entry["uuid"])
+}
@@ -2,14 +2,37 @@ package models
 
 // Asset asset information structure
 type Asset struct {
-	ID      string `json:"id"`
-	Balance string `json:"balance"`
+	ID       string         `json:"id"`
+	Balance  string         `json:"balance"`
+	Metadata *AssetMetadata `json:"metadata,omitempty"`
+}
+
+// AssetMetadata localized asset metadata structure
+type AssetMetadata struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Decimals int    `json:"decimals"`
+	Icon     string `json:"icon,omitempty"`
 }
 
 // SessionAssets session-specific asset information structure
+// A session identifies one character; characters of the same account share AccountID
 type SessionAssets struct {
-	SessionID string            `json:"session_id"`
-	Assets    map[string]string `json:"assets"`
+	SessionID     string            `json:"session_id"`
+	AccountID     string            `json:"account_id"`
+	Name          string            `json:"name"`
+	ImageURL      string            `json:"image_url"`
+	WalletAddress string            `json:"wallet_address"`
+	Server        string            `json:"server"`
+	Assets        map[string]string `json:"assets"`
+	CreatedAt     string            `json:"created_at"`
+	UpdatedAt     string            `json:"updated_at"`
+}
+
+// AccountAssets account-wide (common) asset information structure
+type AccountAssets struct {
+	AccountID string            `json:"account_id"`
+	Common    map[string]string `json:"common"`
 	CreatedAt string            `json:"created_at"`
 	UpdatedAt string            `json:"updated_at"`
 }
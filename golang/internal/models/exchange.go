@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 )
@@ -27,3 +29,20 @@ type ExchangeIntent struct {
 	From   []PairAsset `json:"from"`
 	To     []PairAsset `json:"to"`
 }
+
+// Intent types
+const (
+	IntentTypeAssemble    = "assemble"
+	IntentTypeDisassemble = "disassemble"
+)
+
+// OrderUsage in-game asset usage recorded for a validated order (used by business rules)
+type OrderUsage struct {
+	UUID       string            `json:"uuid"`
+	ProjectID  string            `json:"project_id"`
+	SessionID  string            `json:"session_id"`
+	IntentType string            `json:"intent_type"`
+	Consumed   map[string]uint64 `json:"consumed"`
+	Issued     map[string]uint64 `json:"issued"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
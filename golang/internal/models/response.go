@@ -7,16 +7,83 @@ type V1Data struct {
 	WalletAddress string  `json:"wallet_address"`
 	Server        string  `json:"server"`
 	Assets        []Asset `json:"assets"`
+	// Pending credits of disassemble orders waiting for their result
+	Pending []Asset `json:"pending,omitempty"`
+}
+
+// V2Data v2 guide data structure
+type V2Data struct {
+	Common     []Asset       `json:"common"`
+	Characters []V2Character `json:"characters"`
+}
+
+// V2Character v2 character structure
+type V2Character struct {
+	CharacterID   string  `json:"character_id"`
+	Name          string  `json:"name"`
+	ImageURL      string  `json:"image_url"`
+	WalletAccount string  `json:"wallet_account"`
+	Server        string  `json:"server"`
+	Inventory     []Asset `json:"inventory"`
+	// Pending credits of disassemble orders waiting for their result
+	Pending []Asset `json:"pending,omitempty"`
+}
+
+// WalletMapping wallet enrollment status structure
+type WalletMapping struct {
+	Status        string `json:"status"`
+	WalletAddress string `json:"wallet_address,omitempty"`
+	Method        string `json:"method,omitempty"`
+	EnrolledAt    string `json:"enrolled_at,omitempty"`
+}
+
+// Wallet mapping statuses
+const (
+	WalletMappingEnrolled    = "enrolled"
+	WalletMappingNotEnrolled = "not_enrolled"
+)
+
+// AssetsV1Data v1 assets response data
+type AssetsV1Data struct {
+	V1            V1Data         `json:"v1"`
+	WalletMapping *WalletMapping `json:"wallet_mapping"`
+	Guide         any            `json:"guide"`
+}
+
+// AssetsV2Data v2 assets response data
+type AssetsV2Data struct {
+	V2            V2Data         `json:"v2"`
+	WalletMapping *WalletMapping `json:"wallet_mapping"`
+	Guide         any            `json:"guide"`
+}
+
+// EnrollmentData wallet enrollment response data
+type EnrollmentData struct {
+	SessionID     string         `json:"session_id"`
+	WalletMapping *WalletMapping `json:"wallet_mapping"`
+	// Message text to sign with personal_sign when enrolling by signature
+	Message string `json:"message,omitempty"`
+}
+
+// EnrollRequest wallet enrollment request structure
+// Method "signature" requires wallet_address, message and signature; method "jwt" uses the wallet_address claim
+// of a CROSS_AUTH_JWT issued for the session
+type EnrollRequest struct {
+	Method        string `json:"method"`
+	WalletAddress string `json:"wallet_address"`
+	Message       string `json:"message"`
+	Signature     string `json:"signature"`
+	// CurrentWalletSignature signature of the enrolled wallet over message, required to enroll a different wallet
+	CurrentWalletSignature string `json:"current_wallet_signature"`
 }
 
 // Response API response structure
 type Response struct {
 	Success   bool    `json:"success"`
-	ErrorCode *string `json:"errorCode,omitempty"`
-	Data      struct {
-		V1    V1Data `json:"v1"`
-		Guide any    `json:"guide"`
-	} `json:"data"`
+	ErrorCode *string `json:"errorCode"`
+	// Message problem description of a client error
+	Message string `json:"message,omitempty"`
+	Data    any    `json:"data"`
 }
 
 // ValidateRequest user action validation request structure
@@ -32,9 +99,21 @@ type ValidateRequest struct {
 // ValidateResponse user action validation response structure
 type ValidateResponse struct {
 	Success   bool    `json:"success"`
-	ErrorCode *string `json:"errorCode,omitempty"`
-	Data      struct {
-		UserSig      string `json:"userSig"`
-		ValidatorSig string `json:"validatorSig"`
-	} `json:"data"`
+	ErrorCode *string `json:"errorCode"`
+	// Message problem description of a client error
+	Message string        `json:"message,omitempty"`
+	Details []FieldError  `json:"details,omitempty"`
+	Data    *ValidateData `json:"data"`
+}
+
+// ValidateData user action validation response data
+type ValidateData struct {
+	UserSig      string `json:"userSig"`
+	ValidatorSig string `json:"validatorSig"`
+}
+
+// FieldError field-level validation error
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
 }
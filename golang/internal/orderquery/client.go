@@ -0,0 +1,139 @@
+package orderquery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"sample-game-backend/internal/models"
+)
+
+// DefaultBaseURL CROSS RAMP API endpoint
+const DefaultBaseURL = "https://cross-ramp-api.crosstoken.io"
+
+// Order types
+const (
+	// OrderTypeItemToToken in-game assets exchanged for tokens (assemble)
+	OrderTypeItemToToken = "item_to_token"
+	// OrderTypeTokenToItem tokens exchanged for in-game assets (disassemble)
+	OrderTypeTokenToItem = "token_to_item"
+)
+
+// Order statuses
+const (
+	StatusSuccess = "success"
+	StatusFail    = "fail"
+	StatusFailed  = "failed"
+)
+
+// ErrOrderNotFound CROSS RAMP has no order with the UUID
+var ErrOrderNotFound = errors.New("order not found")
+
+// Order order information returned by CROSS RAMP
+type Order struct {
+	UUID        string             `json:"uuid"`
+	ProjectID   string             `json:"project_id"`
+	SessionID   string             `json:"session_id"`
+	UserAddress string             `json:"user_address"`
+	TxHash      string             `json:"tx_hash"`
+	OrderType   string             `json:"order_type"`
+	OrderStatus string             `json:"order_status"`
+	From        []models.PairAsset `json:"from"`
+	To          []models.PairAsset `json:"to"`
+}
+
+// IntentType intent type of the order ("" for unknown order types)
+func (o *Order) IntentType() string {
+	switch o.OrderType {
+	case OrderTypeItemToToken:
+		return models.IntentTypeAssemble
+	case OrderTypeTokenToItem:
+		return models.IntentTypeDisassemble
+	}
+	return ""
+}
+
+// Succeeded order completed successfully on-chain
+func (o *Order) Succeeded() bool {
+	return strings.EqualFold(o.OrderStatus, StatusSuccess)
+}
+
+// Failed order failed on-chain
+func (o *Order) Failed() bool {
+	return strings.EqualFold(o.OrderStatus, StatusFail) || strings.EqualFold(o.OrderStatus, StatusFailed)
+}
+
+// response CROSS RAMP response envelope
+type response struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Client Order Information Query API client
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient create client for the API at baseURL (DefaultBaseURL when empty)
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// GetOrder query order by chain network and UUID
+func (c *Client) GetOrder(ctx context.Context, network, uuid string) (*Order, error) {
+	query := url.Values{}
+	query.Set("network", network)
+	query.Set("uuid", uuid)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/order?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("order query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+
+	var body response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("order query failed: invalid response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if body.Code == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+	if resp.StatusCode != http.StatusOK || (body.Code != 0 && body.Code != http.StatusOK) {
+		return nil, fmt.Errorf("order query failed: HTTP %d code %d: %s", resp.StatusCode, body.Code, body.Message)
+	}
+
+	var order Order
+	if len(body.Data) == 0 || string(body.Data) == "null" {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, uuid)
+	}
+	if err := json.Unmarshal(body.Data, &order); err != nil {
+		return nil, fmt.Errorf("order query failed: invalid order data: %w", err)
+	}
+	return &order, nil
+}
@@ -0,0 +1,63 @@
+package orderquery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/order", r.URL.Path)
+		assert.Equal(t, "cross-testnet", r.URL.Query().Get("network"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("uuid") {
+		case "order-1":
+			w.Write([]byte(`{
+				"code": 200,
+				"message": "OK",
+				"data": {
+					"uuid": "order-1",
+					"project_id": "project",
+					"session_id": "session",
+					"tx_hash": "0xcffc",
+					"order_type": "token_to_item",
+					"order_status": "success",
+					"from": [{"type": "erc20", "id": "0x1234", "amount": 1}],
+					"to": [{"type": "asset", "id": "asset_money", "amount": 1000}]
+				}
+			}`))
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "order not found", "data": null}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"code": 500, "message": "internal error"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", server.Client())
+
+	order, err := client.GetOrder(context.Background(), "cross-testnet", "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, "session", order.SessionID)
+	assert.Equal(t, models.IntentTypeDisassemble, order.IntentType())
+	assert.True(t, order.Succeeded())
+	assert.False(t, order.Failed())
+	assert.Equal(t, []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1000}}, order.To)
+
+	_, err = client.GetOrder(context.Background(), "cross-testnet", "missing")
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+
+	_, err = client.GetOrder(context.Background(), "cross-testnet", "broken")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrOrderNotFound)
+}
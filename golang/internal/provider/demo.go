@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"sample-game-backend/internal/catalog"
+)
+
+// DemoProvider sample provider that gives every session random balances
+type DemoProvider struct{}
+
+// NewDemoProvider create demo provider
+func NewDemoProvider() *DemoProvider {
+	return &DemoProvider{}
+}
+
+// LoadPlayer generate demo profile with random balances for any session
+func (p *DemoProvider) LoadPlayer(ctx context.Context, sessionID string) (*Player, error) {
+	return &Player{
+		AccountID:     sessionID,
+		Name:          fmt.Sprintf("playerName_%s", sessionID),
+		WalletAddress: "0xaaaa",
+		Server:        "test",
+		Assets:        generateRandomAssets(catalog.ScopeCharacter),
+		Common:        generateRandomAssets(catalog.ScopeAccount),
+	}, nil
+}
+
+// generateRandomAssets generate random balances for every catalog asset of the given scope
+func generateRandomAssets(scope string) map[string]string {
+	assets := make(map[string]string)
+	baseAmount := 100000000
+	for _, id := range catalog.Get().IDsByScope(scope) {
+		assets[id] = strconv.Itoa(rand.Intn(baseAmount) + 500)
+	}
+
+	return assets
+}
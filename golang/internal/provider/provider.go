@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPlayerNotFound returned when the game does not know the session
+var ErrPlayerNotFound = errors.New("player not found")
+
+// Player player profile and balances loaded from the game
+type Player struct {
+	// AccountID account the character belongs to (characters of one account share common assets)
+	AccountID     string
+	Name          string
+	ImageURL      string
+	WalletAddress string
+	Server        string
+	// Assets character inventory balances keyed by asset ID
+	Assets map[string]string
+	// Common account-wide balances keyed by asset ID
+	Common map[string]string
+}
+
+// AssetProvider loads a player's profile and balances from the game
+//
+// Game teams implement this interface against their game database or API.
+// It is consulted the first time a session is seen; balances are tracked by
+// this backend afterwards. Return ErrPlayerNotFound for unknown sessions.
+type AssetProvider interface {
+	LoadPlayer(ctx context.Context, sessionID string) (*Player, error)
+}
+
+// SessionRecognizer optional AssetProvider extension used by session admission control
+//
+// When SESSION_REQUIRE_KNOWN is set, a session that is neither stored nor enrolled
+// is only created if the provider implements this interface and recognizes it.
+// RecognizesSession should be cheap: it runs before balances are loaded.
+type SessionRecognizer interface {
+	RecognizesSession(ctx context.Context, sessionID string) (bool, error)
+}
@@ -0,0 +1,157 @@
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// CodeReceiptMismatch posted receipt differs from the on-chain receipt
+const CodeReceiptMismatch = "RECEIPT_MISMATCH"
+
+// ErrNotConfirmed transaction did not reach the required confirmations in time
+var ErrNotConfirmed = errors.New("transaction not confirmed")
+
+// defaultPollInterval time between chain queries while waiting for confirmations
+const defaultPollInterval = 2 * time.Second
+
+// ChainReader chain queries used to confirm receipts (satisfied by *ethclient.Client)
+type ChainReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethTypes.Receipt, error)
+}
+
+// Confirmer fetch receipts from the chain and compare them with posted receipts
+type Confirmer struct {
+	client        ChainReader
+	confirmations uint64
+	pollInterval  time.Duration
+}
+
+// NewConfirmer create confirmer requiring the given number of confirmations (at least 1)
+func NewConfirmer(client ChainReader, confirmations uint64) *Confirmer {
+	if confirmations == 0 {
+		confirmations = 1
+	}
+	return &Confirmer{
+		client:        client,
+		confirmations: confirmations,
+		pollInterval:  defaultPollInterval,
+	}
+}
+
+// WithPollInterval set time between chain queries
+func (c *Confirmer) WithPollInterval(interval time.Duration) *Confirmer {
+	c.pollInterval = interval
+	return c
+}
+
+// Confirm wait until txHash has the required confirmations and compare its on-chain receipt with posted
+// The receipt is fetched again on every poll, so a reorg before confirmation is picked up, and failed
+// chain queries are retried. Returns ErrNotConfirmed when ctx ends first and *Mismatch when the receipts differ.
+func (c *Confirmer) Confirm(ctx context.Context, posted *ethTypes.Receipt, txHash common.Hash) (*ethTypes.Receipt, error) {
+	onChain, err := c.Fetch(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := compareReceipts(posted, onChain); err != nil {
+		return nil, err
+	}
+	return onChain, nil
+}
+
+// Fetch wait until txHash has the required confirmations and return its on-chain receipt
+// Failed chain queries are retried; returns ErrNotConfirmed when ctx ends first.
+func (c *Confirmer) Fetch(ctx context.Context, txHash common.Hash) (*ethTypes.Receipt, error) {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		onChain, err := c.confirmed(ctx, txHash)
+		if err == nil && onChain != nil {
+			return onChain, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrNotConfirmed, txHash.Hex(), err)
+			}
+			return nil, fmt.Errorf("%w: %s has fewer than %d confirmations", ErrNotConfirmed, txHash.Hex(), c.confirmations)
+		case <-ticker.C:
+		}
+	}
+}
+
+// confirmed get the receipt of txHash once it has enough confirmations (nil while waiting)
+func (c *Confirmer) confirmed(ctx context.Context, txHash common.Hash) (*ethTypes.Receipt, error) {
+	onChain, err := c.client.TransactionReceipt(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch receipt: %w", err)
+	}
+	if onChain.BlockNumber == nil {
+		return nil, nil
+	}
+
+	head, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch block number: %w", err)
+	}
+
+	block := onChain.BlockNumber.Uint64()
+	if head < block || head-block+1 < c.confirmations {
+		return nil, nil
+	}
+	return onChain, nil
+}
+
+// compareReceipts check the posted receipt against the on-chain one
+// Block hash and number are only compared when the posted receipt carries them.
+func compareReceipts(posted, onChain *ethTypes.Receipt) error {
+	if posted.TxHash != onChain.TxHash {
+		return newMismatch(CodeReceiptMismatch, "transaction hash %s, on-chain %s", posted.TxHash.Hex(), onChain.TxHash.Hex())
+	}
+	if posted.Status != onChain.Status {
+		return newMismatch(CodeReceiptMismatch, "status %d, on-chain %d", posted.Status, onChain.Status)
+	}
+	if posted.BlockHash != (common.Hash{}) && posted.BlockHash != onChain.BlockHash {
+		return newMismatch(CodeReceiptMismatch, "block hash %s, on-chain %s", posted.BlockHash.Hex(), onChain.BlockHash.Hex())
+	}
+	if posted.BlockNumber != nil && posted.BlockNumber.Sign() != 0 && posted.BlockNumber.Cmp(onChain.BlockNumber) != 0 {
+		return newMismatch(CodeReceiptMismatch, "block number %s, on-chain %s", posted.BlockNumber, onChain.BlockNumber)
+	}
+	if len(posted.Logs) != len(onChain.Logs) {
+		return newMismatch(CodeReceiptMismatch, "%d logs, on-chain %d", len(posted.Logs), len(onChain.Logs))
+	}
+	for i, log := range posted.Logs {
+		if !sameLog(log, onChain.Logs[i]) {
+			return newMismatch(CodeReceiptMismatch, "log %d differs from on-chain log", i)
+		}
+	}
+	return nil
+}
+
+// sameLog compare log address, topics and data
+func sameLog(a, b *ethTypes.Log) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Address != b.Address || len(a.Topics) != len(b.Topics) || !bytes.Equal(a.Data, b.Data) {
+		return false
+	}
+	for i := range a.Topics {
+		if a.Topics[i] != b.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
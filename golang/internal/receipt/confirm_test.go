@@ -0,0 +1,99 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sendTransfer 시뮬레이션 체인에 전송 트랜잭션을 보내고 블록 생성
+func sendTransfer(t *testing.T) (*simulated.Backend, common.Hash) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend := simulated.NewBackend(ethTypes.GenesisAlloc{
+		sender: {Balance: big.NewInt(params.Ether)},
+	})
+	client := backend.Client()
+	ctx := context.Background()
+
+	chainID, err := client.ChainID(ctx)
+	require.NoError(t, err)
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+
+	tx, err := ethTypes.SignTx(ethTypes.NewTx(&ethTypes.LegacyTx{
+		Nonce:    0,
+		To:       &user,
+		Value:    big.NewInt(1),
+		Gas:      params.TxGas,
+		GasPrice: gasPrice,
+	}), ethTypes.LatestSignerForChainID(chainID), key)
+	require.NoError(t, err)
+	require.NoError(t, client.SendTransaction(ctx, tx))
+	backend.Commit()
+
+	return backend, tx.Hash()
+}
+
+func TestConfirm(t *testing.T) {
+	backend, hash := sendTransfer(t)
+	defer backend.Close()
+
+	onChain, err := backend.Client().TransactionReceipt(context.Background(), hash)
+	require.NoError(t, err)
+	posted := *onChain
+
+	confirmer := NewConfirmer(backend.Client(), 3).WithPollInterval(10 * time.Millisecond)
+
+	// 확인 블록이 부족하면 대기 후 시간 초과
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = confirmer.Confirm(ctx, &posted, hash)
+	assert.True(t, errors.Is(err, ErrNotConfirmed), "expected not confirmed, got %v", err)
+
+	// 대기 중 블록이 생성되면 확인 완료
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		backend.Commit()
+		backend.Commit()
+	}()
+	confirmed, err := confirmer.Confirm(context.Background(), &posted, hash)
+	require.NoError(t, err)
+	assert.Equal(t, onChain.BlockHash, confirmed.BlockHash)
+
+	// 온체인 영수증과 다른 상태
+	tampered := posted
+	tampered.Status = ethTypes.ReceiptStatusFailed
+	_, err = confirmer.Confirm(context.Background(), &tampered, hash)
+	assert.Equal(t, CodeReceiptMismatch, mismatchCode(t, err))
+
+	// 온체인에 없는 로그
+	tampered = posted
+	tampered.Logs = []*ethTypes.Log{erc20Mint(1)}
+	_, err = confirmer.Confirm(context.Background(), &tampered, hash)
+	assert.Equal(t, CodeReceiptMismatch, mismatchCode(t, err))
+}
+
+func TestConfirmUnknownTransaction(t *testing.T) {
+	backend, _ := sendTransfer(t)
+	defer backend.Close()
+
+	confirmer := NewConfirmer(backend.Client(), 1).WithPollInterval(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := confirmer.Confirm(ctx, &ethTypes.Receipt{TxHash: txHash}, txHash)
+	assert.True(t, errors.Is(err, ErrNotConfirmed), "expected not confirmed, got %v", err)
+}
@@ -0,0 +1,213 @@
+package receipt
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Token standards
+const (
+	StandardERC20   = "erc20"
+	StandardERC721  = "erc721"
+	StandardERC1155 = "erc1155"
+)
+
+// Mismatch codes
+const (
+	CodeTxHashMismatch   = "TX_HASH_MISMATCH"
+	CodeTransferMissing  = "TRANSFER_MISSING"
+	CodeTransferMismatch = "TRANSFER_MISMATCH"
+)
+
+// Mismatch receipt disagrees with the result or the stored order
+type Mismatch struct {
+	Code    string
+	Message string
+}
+
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("%s: %s", m.Code, m.Message)
+}
+
+// newMismatch create mismatch with formatted message
+func newMismatch(code, format string, args ...any) *Mismatch {
+	return &Mismatch{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Event signatures
+var (
+	// TopicTransfer ERC20/ERC721 Transfer(address,address,uint256)
+	TopicTransfer = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	// TopicTransferSingle ERC1155 TransferSingle(address,address,address,uint256,uint256)
+	TopicTransferSingle = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	// TopicTransferBatch ERC1155 TransferBatch(address,address,address,uint256[],uint256[])
+	TopicTransferBatch = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+// batchArguments ABI layout of TransferBatch data (ids, values)
+var batchArguments = func() abi.Arguments {
+	uint256Array, err := abi.NewType("uint256[]", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return abi.Arguments{{Type: uint256Array}, {Type: uint256Array}}
+}()
+
+// Transfer token movement decoded from a receipt log
+type Transfer struct {
+	Standard string
+	Contract common.Address
+	From     common.Address
+	To       common.Address
+	// TokenID token ID (nil for ERC20)
+	TokenID *big.Int
+	Amount  *big.Int
+}
+
+// DecodeTransfers decode ERC20, ERC721 and ERC1155 transfer events from receipt logs
+// Logs of other events and malformed transfer logs are skipped
+func DecodeTransfers(logs []*ethTypes.Log) []Transfer {
+	var transfers []Transfer
+	for _, log := range logs {
+		if log == nil || len(log.Topics) == 0 {
+			continue
+		}
+
+		switch log.Topics[0] {
+		case TopicTransfer:
+			switch {
+			case len(log.Topics) == 3 && len(log.Data) == 32:
+				transfers = append(transfers, Transfer{
+					Standard: StandardERC20,
+					Contract: log.Address,
+					From:     common.BytesToAddress(log.Topics[1].Bytes()),
+					To:       common.BytesToAddress(log.Topics[2].Bytes()),
+					Amount:   new(big.Int).SetBytes(log.Data),
+				})
+			case len(log.Topics) == 4:
+				transfers = append(transfers, Transfer{
+					Standard: StandardERC721,
+					Contract: log.Address,
+					From:     common.BytesToAddress(log.Topics[1].Bytes()),
+					To:       common.BytesToAddress(log.Topics[2].Bytes()),
+					TokenID:  log.Topics[3].Big(),
+					Amount:   big.NewInt(1),
+				})
+			}
+		case TopicTransferSingle:
+			if len(log.Topics) != 4 || len(log.Data) != 64 {
+				continue
+			}
+			transfers = append(transfers, Transfer{
+				Standard: StandardERC1155,
+				Contract: log.Address,
+				From:     common.BytesToAddress(log.Topics[2].Bytes()),
+				To:       common.BytesToAddress(log.Topics[3].Bytes()),
+				TokenID:  new(big.Int).SetBytes(log.Data[:32]),
+				Amount:   new(big.Int).SetBytes(log.Data[32:]),
+			})
+		case TopicTransferBatch:
+			if len(log.Topics) != 4 {
+				continue
+			}
+			values, err := batchArguments.Unpack(log.Data)
+			if err != nil || len(values) != 2 {
+				continue
+			}
+			ids, okIDs := values[0].([]*big.Int)
+			amounts, okAmounts := values[1].([]*big.Int)
+			if !okIDs || !okAmounts || len(ids) != len(amounts) {
+				continue
+			}
+			for i := range ids {
+				transfers = append(transfers, Transfer{
+					Standard: StandardERC1155,
+					Contract: log.Address,
+					From:     common.BytesToAddress(log.Topics[2].Bytes()),
+					To:       common.BytesToAddress(log.Topics[3].Bytes()),
+					TokenID:  ids[i],
+					Amount:   amounts[i],
+				})
+			}
+		}
+	}
+	return transfers
+}
+
+// Flow direction of the expected transfers
+type Flow struct {
+	From common.Address
+	To   common.Address
+}
+
+// Mint tokens minted to user (from the zero address)
+func Mint(user common.Address) *Flow {
+	return &Flow{To: user}
+}
+
+// Burn tokens burned from user (to the zero address)
+func Burn(user common.Address) *Flow {
+	return &Flow{From: user}
+}
+
+// Verify check that the receipt belongs to txHash and its transfers match the expected tokens
+// Each expected token's amount must equal the total its contract transferred in flow (raw on-chain units);
+// a nil flow counts transfers in any direction. Transfer logs are only checked for successful receipts.
+func Verify(receipt *ethTypes.Receipt, txHash common.Hash, expected []models.PairAsset, flow *Flow) error {
+	if receipt.TxHash != txHash {
+		return newMismatch(CodeTxHashMismatch, "receipt transaction hash %s does not match tx_hash %s", receipt.TxHash.Hex(), txHash.Hex())
+	}
+	if receipt.Status != ethTypes.ReceiptStatusSuccessful {
+		return nil
+	}
+
+	totals := make(map[string]*big.Int)
+	standards := make(map[string]string)
+	for _, transfer := range DecodeTransfers(receipt.Logs) {
+		contract := strings.ToLower(transfer.Contract.Hex())
+		if totals[contract] == nil {
+			totals[contract] = new(big.Int)
+		}
+		standards[contract] = transfer.Standard
+		// Transfers in another direction (e.g. to a third party) do not count toward the order
+		if flow == nil || (transfer.From == flow.From && transfer.To == flow.To) {
+			totals[contract].Add(totals[contract], transfer.Amount)
+		}
+	}
+
+	for _, token := range expected {
+		contract := strings.ToLower(token.AssetID)
+		total, ok := totals[contract]
+		if !ok {
+			return newMismatch(CodeTransferMissing, "no %s transfer of %s in receipt logs", token.Type, token.AssetID)
+		}
+		if flow != nil && total.Sign() == 0 && token.Amount > 0 {
+			return newMismatch(CodeTransferMismatch, "no transfer of %s from %s to %s", token.AssetID, flow.From.Hex(), flow.To.Hex())
+		}
+		if !standardMatches(token.Type, standards[contract]) {
+			return newMismatch(CodeTransferMismatch, "transfer of %s is %s, expected %s", token.AssetID, standards[contract], token.Type)
+		}
+		if total.Cmp(new(big.Int).SetUint64(uint64(token.Amount))) != 0 {
+			return newMismatch(CodeTransferMismatch, "transfer amount of %s is %s, expected %d", token.AssetID, total.String(), token.Amount)
+		}
+	}
+	return nil
+}
+
+// standardMatches compare intent token type with the decoded standard
+// ERC20 and ERC721 share the Transfer event, so only ERC1155 is distinguished reliably
+func standardMatches(tokenType, standard string) bool {
+	tokenType = strings.ToLower(tokenType)
+	if tokenType == StandardERC1155 || standard == StandardERC1155 {
+		return tokenType == standard
+	}
+	return tokenType == StandardERC20 || tokenType == StandardERC721
+}
@@ -0,0 +1,148 @@
+package receipt
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	erc20Contract   = common.HexToAddress("0x1234567890123456789012345678901234567890")
+	erc1155Contract = common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcdefabcd")
+	user            = common.HexToAddress("0xB777C937fa1afC99606aFa85c5b83cFe7f82BabD")
+	txHash          = common.HexToHash("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+)
+
+func addressTopic(address common.Address) common.Hash {
+	return common.BytesToHash(address.Bytes())
+}
+
+func erc20Mint(amount int64) *ethTypes.Log {
+	return &ethTypes.Log{
+		Address: erc20Contract,
+		Topics:  []common.Hash{TopicTransfer, addressTopic(common.Address{}), addressTopic(user)},
+		Data:    common.LeftPadBytes(big.NewInt(amount).Bytes(), 32),
+	}
+}
+
+func erc1155Single(id, amount int64) *ethTypes.Log {
+	return &ethTypes.Log{
+		Address: erc1155Contract,
+		Topics:  []common.Hash{TopicTransferSingle, addressTopic(user), addressTopic(user), addressTopic(common.Address{})},
+		Data:    append(common.LeftPadBytes(big.NewInt(id).Bytes(), 32), common.LeftPadBytes(big.NewInt(amount).Bytes(), 32)...),
+	}
+}
+
+func erc1155Batch(t *testing.T, ids, amounts []*big.Int) *ethTypes.Log {
+	data, err := batchArguments.Pack(ids, amounts)
+	require.NoError(t, err)
+	return &ethTypes.Log{
+		Address: erc1155Contract,
+		Topics:  []common.Hash{TopicTransferBatch, addressTopic(user), addressTopic(user), addressTopic(common.Address{})},
+		Data:    data,
+	}
+}
+
+// mismatchCode 불일치 코드 추출
+func mismatchCode(t *testing.T, err error) string {
+	var mismatch *Mismatch
+	require.True(t, errors.As(err, &mismatch), "expected mismatch, got %v", err)
+	return mismatch.Code
+}
+
+func TestDecodeTransfers(t *testing.T) {
+	erc721 := &ethTypes.Log{
+		Address: erc20Contract,
+		Topics:  []common.Hash{TopicTransfer, addressTopic(common.Address{}), addressTopic(user), common.BigToHash(big.NewInt(7))},
+	}
+	unrelated := &ethTypes.Log{Address: erc20Contract, Topics: []common.Hash{common.HexToHash("0x01")}}
+
+	transfers := DecodeTransfers([]*ethTypes.Log{
+		erc20Mint(1000),
+		erc721,
+		erc1155Single(1, 5),
+		erc1155Batch(t, []*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(3), big.NewInt(4)}),
+		unrelated,
+	})
+	require.Len(t, transfers, 5)
+
+	assert.Equal(t, StandardERC20, transfers[0].Standard)
+	assert.Equal(t, user, transfers[0].To)
+	assert.Equal(t, int64(1000), transfers[0].Amount.Int64())
+
+	assert.Equal(t, StandardERC721, transfers[1].Standard)
+	assert.Equal(t, int64(7), transfers[1].TokenID.Int64())
+
+	assert.Equal(t, StandardERC1155, transfers[2].Standard)
+	assert.Equal(t, user, transfers[2].From)
+	assert.Equal(t, int64(5), transfers[2].Amount.Int64())
+
+	assert.Equal(t, int64(2), transfers[4].TokenID.Int64())
+	assert.Equal(t, int64(4), transfers[4].Amount.Int64())
+}
+
+func TestVerify(t *testing.T) {
+	receipt := &ethTypes.Receipt{
+		Status: ethTypes.ReceiptStatusSuccessful,
+		TxHash: txHash,
+		Logs:   []*ethTypes.Log{erc20Mint(1), erc1155Single(1, 2), erc1155Single(2, 3)},
+	}
+	expected := []models.PairAsset{
+		{Type: "erc20", AssetID: "0x1234567890123456789012345678901234567890", Amount: 1},
+		{Type: "erc1155", AssetID: "0xABCDEFABCDEFABCDEFABCDEFABCDEFABCDEFABCD", Amount: 5},
+	}
+	assert.NoError(t, Verify(receipt, txHash, expected, nil))
+
+	// tx_hash 불일치
+	err := Verify(receipt, common.HexToHash("0x01"), expected, nil)
+	assert.Equal(t, CodeTxHashMismatch, mismatchCode(t, err))
+
+	// 수량 불일치
+	err = Verify(receipt, txHash, []models.PairAsset{{Type: "erc20", AssetID: erc20Contract.Hex(), Amount: 2}}, nil)
+	assert.Equal(t, CodeTransferMismatch, mismatchCode(t, err))
+
+	// 표준 불일치
+	err = Verify(receipt, txHash, []models.PairAsset{{Type: "erc1155", AssetID: erc20Contract.Hex(), Amount: 1}}, nil)
+	assert.Equal(t, CodeTransferMismatch, mismatchCode(t, err))
+
+	// 로그 누락
+	err = Verify(&ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful, TxHash: txHash}, txHash, expected, nil)
+	assert.Equal(t, CodeTransferMissing, mismatchCode(t, err))
+
+	// 실패한 트랜잭션은 로그를 검사하지 않음
+	assert.NoError(t, Verify(&ethTypes.Receipt{Status: ethTypes.ReceiptStatusFailed, TxHash: txHash}, txHash, expected, nil))
+}
+
+func TestVerifyFlow(t *testing.T) {
+	other := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	mintTo := func(to common.Address, amount int64) *ethTypes.Log {
+		log := erc20Mint(amount)
+		log.Topics[2] = addressTopic(to)
+		return log
+	}
+	erc20 := []models.PairAsset{{Type: "erc20", AssetID: erc20Contract.Hex(), Amount: 5}}
+	erc1155 := []models.PairAsset{{Type: "erc1155", AssetID: erc1155Contract.Hex(), Amount: 2}}
+
+	// 사용자에게 발행된 토큰만 인정
+	minted := &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful, TxHash: txHash, Logs: []*ethTypes.Log{erc20Mint(5)}}
+	assert.NoError(t, Verify(minted, txHash, erc20, Mint(user)))
+	assert.Equal(t, CodeTransferMismatch, mismatchCode(t, Verify(minted, txHash, erc20, Mint(other))))
+	assert.Equal(t, CodeTransferMismatch, mismatchCode(t, Verify(minted, txHash, erc20, Burn(user))))
+
+	// 다른 주소로 발행된 수량은 합산하지 않음
+	split := &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful, TxHash: txHash, Logs: []*ethTypes.Log{erc20Mint(3), mintTo(other, 2)}}
+	assert.NoError(t, Verify(split, txHash, erc20, nil))
+	assert.Equal(t, CodeTransferMismatch, mismatchCode(t, Verify(split, txHash, erc20, Mint(user))))
+
+	// 분해는 사용자로부터 소각
+	burned := &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful, TxHash: txHash, Logs: []*ethTypes.Log{erc1155Single(1, 2)}}
+	assert.NoError(t, Verify(burned, txHash, erc1155, Burn(user)))
+	assert.Equal(t, CodeTransferMismatch, mismatchCode(t, Verify(burned, txHash, erc1155, Mint(user))))
+}
@@ -0,0 +1,375 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"sample-game-backend/internal/models"
+)
+
+// Rule types
+const (
+	// TypeAssetCap caps the amount of an asset consumed or issued per period
+	TypeAssetCap = "asset_cap"
+	// TypeOrderCount caps the number of orders per character per period
+	TypeOrderCount = "order_count"
+	// TypeOrderAmount bounds the amount of an asset in a single order
+	TypeOrderAmount = "order_amount"
+	// TypeCooldown enforces a minimum interval between orders of a character
+	TypeCooldown = "cooldown"
+)
+
+// Periods
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+// Directions for asset caps
+const (
+	// DirectionConsume in-game assets deducted by assemble intents
+	DirectionConsume = "consume"
+	// DirectionIssue in-game assets credited by disassemble intents
+	DirectionIssue = "issue"
+)
+
+// Scopes for asset caps
+const (
+	ScopeCharacter = "character"
+	ScopeProject   = "project"
+)
+
+// DefaultProject key of the rules applied to projects without their own rules
+const DefaultProject = "*"
+
+// Rule business rule definition
+type Rule struct {
+	Type string `json:"type"`
+	// IntentType limit rule to "assemble" or "disassemble" (all intents when empty)
+	IntentType string `json:"intent_type,omitempty"`
+	AssetID    string `json:"asset_id,omitempty"`
+	Period     string `json:"period,omitempty"`
+	Direction  string `json:"direction,omitempty"`
+	Scope      string `json:"scope,omitempty"`
+	Min        uint64 `json:"min,omitempty"`
+	Max        uint64 `json:"max,omitempty"`
+	// Cooldown minimum interval such as "30s" (cooldown rules)
+	Cooldown string `json:"cooldown,omitempty"`
+
+	cooldown time.Duration
+}
+
+// Config rules configuration file structure
+type Config struct {
+	// Timezone used to compute daily/weekly periods (UTC when empty)
+	Timezone string            `json:"timezone,omitempty"`
+	Projects map[string][]Rule `json:"projects"`
+}
+
+// UsageSource provides order usage history for rule evaluation
+type UsageSource interface {
+	CharacterUsage(projectID, sessionID string, since time.Time) ([]models.OrderUsage, error)
+	ProjectUsage(projectID string, since time.Time) ([]models.OrderUsage, error)
+}
+
+// Request order to evaluate
+type Request struct {
+	ProjectID string
+	SessionID string
+	Intent    models.ExchangeIntent
+	Now       time.Time
+}
+
+// Engine business rules engine
+type Engine struct {
+	location *time.Location
+	projects map[string][]Rule
+}
+
+// Parse parse rules configuration JSON
+func Parse(data []byte) (*Engine, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid rules config: %w", err)
+	}
+	return NewEngine(cfg)
+}
+
+// Load load rules configuration from file
+func Load(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules config: %w", err)
+	}
+	return Parse(data)
+}
+
+// NewEngine create engine from configuration
+func NewEngine(cfg Config) (*Engine, error) {
+	location := time.UTC
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules config: %w", err)
+		}
+		location = loc
+	}
+
+	projects := make(map[string][]Rule, len(cfg.Projects))
+	for projectID, projectRules := range cfg.Projects {
+		validated := make([]Rule, 0, len(projectRules))
+		for i, rule := range projectRules {
+			if err := rule.validate(); err != nil {
+				return nil, fmt.Errorf("invalid rules config: project %s rule %d: %w", projectID, i, err)
+			}
+			validated = append(validated, rule)
+		}
+		projects[projectID] = validated
+	}
+
+	return &Engine{location: location, projects: projects}, nil
+}
+
+// validate check rule definition and fill defaults
+func (r *Rule) validate() error {
+	switch r.IntentType {
+	case "", models.IntentTypeAssemble, models.IntentTypeDisassemble:
+	default:
+		return fmt.Errorf("unknown intent_type %s", r.IntentType)
+	}
+
+	switch r.Type {
+	case TypeAssetCap:
+		if r.AssetID == "" || r.Max == 0 {
+			return fmt.Errorf("asset_cap requires asset_id and max")
+		}
+		if r.Direction != DirectionConsume && r.Direction != DirectionIssue {
+			return fmt.Errorf("asset_cap requires direction consume or issue")
+		}
+		if r.Scope == "" {
+			r.Scope = ScopeCharacter
+		}
+		if r.Scope != ScopeCharacter && r.Scope != ScopeProject {
+			return fmt.Errorf("unknown scope %s", r.Scope)
+		}
+		return validatePeriod(r.Period)
+	case TypeOrderCount:
+		if r.Max == 0 {
+			return fmt.Errorf("order_count requires max")
+		}
+		return validatePeriod(r.Period)
+	case TypeOrderAmount:
+		if r.AssetID == "" || (r.Min == 0 && r.Max == 0) {
+			return fmt.Errorf("order_amount requires asset_id and min or max")
+		}
+		if r.Max != 0 && r.Min > r.Max {
+			return fmt.Errorf("order_amount min is greater than max")
+		}
+	case TypeCooldown:
+		d, err := time.ParseDuration(r.Cooldown)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("cooldown requires a positive duration")
+		}
+		r.cooldown = d
+	default:
+		return fmt.Errorf("unknown rule type %s", r.Type)
+	}
+	return nil
+}
+
+// validatePeriod check period value
+func validatePeriod(period string) error {
+	if period != PeriodDaily && period != PeriodWeekly {
+		return fmt.Errorf("period must be daily or weekly")
+	}
+	return nil
+}
+
+// RulesFor return rules for project (default rules when the project has none)
+func (e *Engine) RulesFor(projectID string) []Rule {
+	if projectRules, ok := e.projects[projectID]; ok {
+		return projectRules
+	}
+	return e.projects[DefaultProject]
+}
+
+// Evaluate evaluate order against the project's rules
+// Returns *Violation when a rule rejects the order
+func (e *Engine) Evaluate(req Request, source UsageSource) error {
+	if req.Now.IsZero() {
+		req.Now = time.Now()
+	}
+	consumed, issued := UsageFromIntent(req.Intent)
+
+	for _, rule := range e.RulesFor(req.ProjectID) {
+		if rule.IntentType != "" && rule.IntentType != req.Intent.Type {
+			continue
+		}
+
+		var err error
+		switch rule.Type {
+		case TypeAssetCap:
+			err = e.evaluateAssetCap(rule, req, consumed, issued, source)
+		case TypeOrderCount:
+			err = e.evaluateOrderCount(rule, req, source)
+		case TypeOrderAmount:
+			err = evaluateOrderAmount(rule, consumed, issued)
+		case TypeCooldown:
+			err = evaluateCooldown(rule, req, source)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evaluateAssetCap check the asset's consumed/issued total for the period
+func (e *Engine) evaluateAssetCap(rule Rule, req Request, consumed, issued map[string]uint64, source UsageSource) error {
+	amounts := consumed
+	if rule.Direction == DirectionIssue {
+		amounts = issued
+	}
+	amount, ok := amounts[rule.AssetID]
+	if !ok {
+		return nil
+	}
+
+	since := e.PeriodStart(rule.Period, req.Now)
+	var history []models.OrderUsage
+	var err error
+	if rule.Scope == ScopeProject {
+		history, err = source.ProjectUsage(req.ProjectID, since)
+	} else {
+		history, err = source.CharacterUsage(req.ProjectID, req.SessionID, since)
+	}
+	if err != nil {
+		return err
+	}
+
+	total := amount
+	for _, usage := range history {
+		if rule.Direction == DirectionIssue {
+			total += usage.Issued[rule.AssetID]
+		} else {
+			total += usage.Consumed[rule.AssetID]
+		}
+	}
+
+	if total > rule.Max {
+		code := CodeDailyLimitExceeded
+		if rule.Period == PeriodWeekly {
+			code = CodeWeeklyLimitExceeded
+		}
+		return newViolation(rule, code, "%s %s limit for %s exceeded: %d > %d", rule.Period, rule.Direction, rule.AssetID, total, rule.Max)
+	}
+	return nil
+}
+
+// evaluateOrderCount check the character's order count for the period
+func (e *Engine) evaluateOrderCount(rule Rule, req Request, source UsageSource) error {
+	history, err := source.CharacterUsage(req.ProjectID, req.SessionID, e.PeriodStart(rule.Period, req.Now))
+	if err != nil {
+		return err
+	}
+
+	count := uint64(1)
+	for _, usage := range history {
+		if rule.IntentType == "" || usage.IntentType == rule.IntentType {
+			count++
+		}
+	}
+
+	if count > rule.Max {
+		return newViolation(rule, CodeOrderCountExceeded, "%s order count limit exceeded: %d > %d", rule.Period, count, rule.Max)
+	}
+	return nil
+}
+
+// evaluateOrderAmount check the asset amount of a single order
+func evaluateOrderAmount(rule Rule, consumed, issued map[string]uint64) error {
+	amount, ok := consumed[rule.AssetID]
+	if !ok {
+		amount, ok = issued[rule.AssetID]
+	}
+	if !ok {
+		return nil
+	}
+
+	if amount < rule.Min {
+		return newViolation(rule, CodeOrderAmountTooLow, "order amount of %s below minimum: %d < %d", rule.AssetID, amount, rule.Min)
+	}
+	if rule.Max != 0 && amount > rule.Max {
+		return newViolation(rule, CodeOrderAmountTooHigh, "order amount of %s above maximum: %d > %d", rule.AssetID, amount, rule.Max)
+	}
+	return nil
+}
+
+// evaluateCooldown check the interval since the character's last order
+func evaluateCooldown(rule Rule, req Request, source UsageSource) error {
+	history, err := source.CharacterUsage(req.ProjectID, req.SessionID, req.Now.Add(-rule.cooldown))
+	if err != nil {
+		return err
+	}
+
+	for _, usage := range history {
+		if rule.IntentType == "" || usage.IntentType == rule.IntentType {
+			remaining := rule.cooldown - req.Now.Sub(usage.CreatedAt)
+			return newViolation(rule, CodeCooldownActive, "cooldown active for %s", remaining.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// UsageLookback how far back order usage can still affect a rule
+// Covers the longest period or cooldown with a day of margin for timezone and DST offsets.
+func (e *Engine) UsageLookback() time.Duration {
+	var lookback time.Duration
+	for _, projectRules := range e.projects {
+		for _, rule := range projectRules {
+			switch rule.Type {
+			case TypeAssetCap, TypeOrderCount:
+				days := 1
+				if rule.Period == PeriodWeekly {
+					days = 7
+				}
+				lookback = max(lookback, time.Duration(days+1)*24*time.Hour)
+			case TypeCooldown:
+				lookback = max(lookback, rule.cooldown)
+			}
+		}
+	}
+	return lookback
+}
+
+// PeriodStart return start of the daily/weekly period containing now
+// Weeks start on Monday
+func (e *Engine) PeriodStart(period string, now time.Time) time.Time {
+	local := now.In(e.location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, e.location)
+	if period == PeriodWeekly {
+		offset := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -offset)
+	}
+	return start
+}
+
+// UsageFromIntent in-game assets consumed (assemble from) and issued (disassemble to) by intent
+func UsageFromIntent(intent models.ExchangeIntent) (map[string]uint64, map[string]uint64) {
+	consumed := make(map[string]uint64)
+	issued := make(map[string]uint64)
+	switch intent.Type {
+	case models.IntentTypeAssemble:
+		for _, from := range intent.From {
+			consumed[from.AssetID] += uint64(from.Amount)
+		}
+	case models.IntentTypeDisassemble:
+		for _, to := range intent.To {
+			issued[to.AssetID] += uint64(to.Amount)
+		}
+	}
+	return consumed, issued
+}
@@ -0,0 +1,184 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySource 테스트용 사용량 저장소
+type memorySource struct {
+	usages []models.OrderUsage
+}
+
+func (s *memorySource) CharacterUsage(projectID, sessionID string, since time.Time) ([]models.OrderUsage, error) {
+	var result []models.OrderUsage
+	for _, usage := range s.usages {
+		if usage.ProjectID == projectID && usage.SessionID == sessionID && !usage.CreatedAt.Before(since) {
+			result = append(result, usage)
+		}
+	}
+	return result, nil
+}
+
+func (s *memorySource) ProjectUsage(projectID string, since time.Time) ([]models.OrderUsage, error) {
+	var result []models.OrderUsage
+	for _, usage := range s.usages {
+		if usage.ProjectID == projectID && !usage.CreatedAt.Before(since) {
+			result = append(result, usage)
+		}
+	}
+	return result, nil
+}
+
+func assembleIntent(assetID string, amount uint) models.ExchangeIntent {
+	return models.ExchangeIntent{
+		Type:   models.IntentTypeAssemble,
+		Method: "mint",
+		From:   []models.PairAsset{{Type: "asset", AssetID: assetID, Amount: amount}},
+		To:     []models.PairAsset{{Type: "erc20", AssetID: "0x1234", Amount: 1}},
+	}
+}
+
+func disassembleIntent(assetID string, amount uint) models.ExchangeIntent {
+	return models.ExchangeIntent{
+		Type:   models.IntentTypeDisassemble,
+		Method: "burn",
+		From:   []models.PairAsset{{Type: "erc20", AssetID: "0x1234", Amount: 1}},
+		To:     []models.PairAsset{{Type: "asset", AssetID: assetID, Amount: amount}},
+	}
+}
+
+// violationCode 위반 코드 추출
+func violationCode(t *testing.T, err error) string {
+	var violation *Violation
+	require.True(t, errors.As(err, &violation), "expected violation, got %v", err)
+	return violation.Code
+}
+
+func TestAssetCap(t *testing.T) {
+	engine, err := Parse([]byte(`{
+		"projects": {
+			"*": [
+				{"type": "asset_cap", "asset_id": "asset_money", "period": "daily", "direction": "issue", "max": 1000},
+				{"type": "asset_cap", "asset_id": "asset_money", "period": "weekly", "direction": "consume", "max": 5000, "scope": "project"}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC) // 수요일
+	source := &memorySource{usages: []models.OrderUsage{
+		{UUID: "1", ProjectID: "p", SessionID: "s", IntentType: "disassemble", Issued: map[string]uint64{"asset_money": 800}, CreatedAt: now.Add(-time.Hour)},
+		{UUID: "2", ProjectID: "p", SessionID: "s", IntentType: "disassemble", Issued: map[string]uint64{"asset_money": 800}, CreatedAt: now.Add(-24 * time.Hour)}, // 전날
+		{UUID: "3", ProjectID: "p", SessionID: "other", IntentType: "assemble", Consumed: map[string]uint64{"asset_money": 4500}, CreatedAt: now.Add(-48 * time.Hour)},
+	}}
+
+	// 일일 발행 한도: 오늘 800 + 200 = 1000 허용, 800 + 201 거부
+	assert.NoError(t, engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: disassembleIntent("asset_money", 200), Now: now}, source))
+	err = engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: disassembleIntent("asset_money", 201), Now: now}, source)
+	assert.Equal(t, CodeDailyLimitExceeded, violationCode(t, err))
+
+	// 프로젝트 주간 소모 한도: 다른 캐릭터 사용량도 합산
+	assert.NoError(t, engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: assembleIntent("asset_money", 500), Now: now}, source))
+	err = engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: assembleIntent("asset_money", 501), Now: now}, source)
+	assert.Equal(t, CodeWeeklyLimitExceeded, violationCode(t, err))
+}
+
+func TestOrderCountAndCooldown(t *testing.T) {
+	engine, err := Parse([]byte(`{
+		"projects": {
+			"p": [
+				{"type": "order_count", "period": "daily", "max": 2, "intent_type": "assemble"},
+				{"type": "cooldown", "cooldown": "30s"}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	source := &memorySource{usages: []models.OrderUsage{
+		{UUID: "1", ProjectID: "p", SessionID: "s", IntentType: "assemble", CreatedAt: now.Add(-10 * time.Second)},
+	}}
+
+	// 쿨다운 중
+	err = engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: assembleIntent("asset_money", 1), Now: now}, source)
+	assert.Equal(t, CodeCooldownActive, violationCode(t, err))
+
+	// 쿨다운 이후 두 번째 주문은 허용
+	later := now.Add(time.Minute)
+	assert.NoError(t, engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: assembleIntent("asset_money", 1), Now: later}, source))
+
+	// 세 번째 주문은 일일 주문 수 초과
+	source.usages = append(source.usages, models.OrderUsage{UUID: "2", ProjectID: "p", SessionID: "s", IntentType: "assemble", CreatedAt: later})
+	err = engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: assembleIntent("asset_money", 1), Now: later.Add(time.Minute)}, source)
+	assert.Equal(t, CodeOrderCountExceeded, violationCode(t, err))
+
+	// 다른 프로젝트는 기본 규칙이 없으므로 허용
+	assert.NoError(t, engine.Evaluate(Request{ProjectID: "other", SessionID: "s", Intent: assembleIntent("asset_money", 1), Now: now}, source))
+}
+
+func TestOrderAmount(t *testing.T) {
+	engine, err := Parse([]byte(`{
+		"projects": {
+			"*": [{"type": "order_amount", "asset_id": "asset_money", "min": 100, "max": 1000}]
+		}
+	}`))
+	require.NoError(t, err)
+
+	source := &memorySource{}
+	err = engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: assembleIntent("asset_money", 99)}, source)
+	assert.Equal(t, CodeOrderAmountTooLow, violationCode(t, err))
+
+	err = engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: disassembleIntent("asset_money", 1001)}, source)
+	assert.Equal(t, CodeOrderAmountTooHigh, violationCode(t, err))
+
+	assert.NoError(t, engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: assembleIntent("asset_money", 500)}, source))
+	assert.NoError(t, engine.Evaluate(Request{ProjectID: "p", SessionID: "s", Intent: assembleIntent("asset_gold", 1)}, source), "other assets are not bounded")
+}
+
+func TestPeriodStart(t *testing.T) {
+	engine, err := Parse([]byte(`{"timezone": "Asia/Seoul", "projects": {}}`))
+	require.NoError(t, err)
+
+	// 2025-01-15 (수) 01:00 UTC = 10:00 KST
+	now := time.Date(2025, 1, 15, 1, 0, 0, 0, time.UTC)
+	seoul, _ := time.LoadLocation("Asia/Seoul")
+	assert.True(t, engine.PeriodStart(PeriodDaily, now).Equal(time.Date(2025, 1, 15, 0, 0, 0, 0, seoul)))
+	assert.True(t, engine.PeriodStart(PeriodWeekly, now).Equal(time.Date(2025, 1, 13, 0, 0, 0, 0, seoul)), "weeks start on Monday")
+}
+
+func TestUsageLookback(t *testing.T) {
+	engine, err := Parse([]byte(`{"projects": {}}`))
+	require.NoError(t, err)
+	assert.Zero(t, engine.UsageLookback())
+
+	// 가장 긴 기간 (주간 + 여유 하루)
+	engine, err = Parse([]byte(`{
+		"projects": {
+			"p": [{"type": "order_count", "period": "daily", "max": 2}, {"type": "cooldown", "cooldown": "72h"}],
+			"q": [{"type": "asset_cap", "asset_id": "asset_money", "direction": "consume", "period": "weekly", "max": 10}]
+		}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, 8*24*time.Hour, engine.UsageLookback())
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	invalid := []string{
+		`{"projects": {"*": [{"type": "unknown"}]}}`,
+		`{"projects": {"*": [{"type": "asset_cap", "asset_id": "a", "period": "daily", "max": 1}]}}`,
+		`{"projects": {"*": [{"type": "order_count", "period": "monthly", "max": 1}]}}`,
+		`{"projects": {"*": [{"type": "cooldown", "cooldown": "soon"}]}}`,
+		`{"projects": {"*": [{"type": "order_amount", "asset_id": "a", "min": 10, "max": 1}]}}`,
+	}
+	for _, config := range invalid {
+		_, err := Parse([]byte(config))
+		assert.Error(t, err, config)
+	}
+}
@@ -0,0 +1,34 @@
+package rules
+
+import "fmt"
+
+// Violation error codes
+const (
+	CodeDailyLimitExceeded  = "DAILY_LIMIT_EXCEEDED"
+	CodeWeeklyLimitExceeded = "WEEKLY_LIMIT_EXCEEDED"
+	CodeOrderCountExceeded  = "ORDER_COUNT_EXCEEDED"
+	CodeOrderAmountTooLow   = "ORDER_AMOUNT_TOO_LOW"
+	CodeOrderAmountTooHigh  = "ORDER_AMOUNT_TOO_HIGH"
+	CodeCooldownActive      = "COOLDOWN_ACTIVE"
+)
+
+// Violation rule rejection with error code
+type Violation struct {
+	Rule    Rule
+	Code    string
+	Message string
+}
+
+// Error implement error interface
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Code, v.Message)
+}
+
+// newViolation create violation for rule
+func newViolation(rule Rule, code, format string, args ...any) *Violation {
+	return &Violation{
+		Rule:    rule,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"sample-game-backend/internal/catalog"
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrInvalidAdjustment manual adjustment without an amount, reason or operator, or for an asset outside the catalog
+var ErrInvalidAdjustment = errors.New("invalid balance adjustment")
+
+// AdjustBalance credit (positive amount) or debit (negative amount) an asset of a stored session on behalf of an operator
+// The change is recorded in the ledger with the operator and reason.
+func AdjustBalance(sessionID, assetID string, amount int64, operator, reason string) (*models.SessionAssets, error) {
+	reason = strings.TrimSpace(reason)
+	switch {
+	case amount == 0 || amount > math.MaxUint32 || amount < -math.MaxUint32:
+		return nil, fmt.Errorf("%w: amount must be a non-zero 32-bit value", ErrInvalidAdjustment)
+	case reason == "":
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidAdjustment)
+	case operator == "":
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidAdjustment)
+	}
+	if _, ok := catalog.Get().Lookup(assetID); !ok {
+		return nil, fmt.Errorf("%w: asset %s is not in the catalog", ErrInvalidAdjustment, assetID)
+	}
+
+	// Only stored sessions are adjusted; unknown sessions are never loaded from the provider here
+	session, err := database.GetSessionAssets(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("%w: %s", database.ErrSessionNotFound, sessionID)
+	}
+
+	ref := database.LedgerRef{Source: database.LedgerSourceAdjustment, Operator: operator, Reason: reason}
+	assets := []models.PairAsset{{Type: PairAssetTypeAsset, AssetID: assetID, Amount: uint(abs(amount))}}
+	if amount > 0 {
+		err = database.AddAssets(sessionID, assets, ref)
+	} else {
+		err = database.CheckAndDeductAssets(sessionID, assets, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Warn("AdjustBalance", "audit", "manual_adjustment", "operator", operator, "sessionID", sessionID, "assetID", assetID, "amount", amount, "reason", reason)
+	return database.GetSessionAssets(sessionID)
+}
+
+// abs absolute value of n
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Operator action errors
+var (
+	ErrReasonRequired     = errors.New("operator and reason are required")
+	ErrNoValidatedIntent  = errors.New("order has no validated intent")
+	ErrReceiptUnavailable = errors.New("receipt unavailable")
+)
+
+// RetrySettlement re-run the result path of an order with a supplied receipt, or one fetched from the chain
+// The tx hash defaults to the supplied receipt's, then to the hash recorded on the order.
+func RetrySettlement(ctx context.Context, uuid, txHash string, supplied *ethTypes.Receipt, operator, reason string) (*database.UUIDMapping, error) {
+	order, note, err := prepareSettlement(uuid, "retry", operator, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if txHash == "" && supplied != nil {
+		txHash = supplied.TxHash.Hex()
+	}
+	if txHash == "" {
+		txHash = order.TxHash
+	}
+	if txHash == "" {
+		return nil, fmt.Errorf("%w: no tx hash supplied or recorded for %s", ErrReceiptUnavailable, uuid)
+	}
+
+	rcpt := supplied
+	if rcpt == nil {
+		receiptMu.RLock()
+		confirmer, timeout := receiptConfirmer, confirmTimeout
+		receiptMu.RUnlock()
+		if confirmer == nil {
+			return nil, fmt.Errorf("%w: no receipt supplied and RECEIPT_RPC_URL is not configured", ErrReceiptUnavailable)
+		}
+
+		fetchCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			fetchCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		if rcpt, err = confirmer.Fetch(fetchCtx, common.HexToHash(txHash)); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := database.NoteOrder(uuid, note); err != nil {
+		return nil, err
+	}
+	err = ProcessResult(ctx, models.ExchangeReq{
+		UUID:    uuid,
+		TxHash:  common.HexToHash(txHash),
+		Receipt: *rcpt,
+		Intent:  *order.Intent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Warn("RetrySettlement", "audit", "operator_retry", "operator", operator, "uuid", uuid, "txHash", txHash, "receiptStatus", rcpt.Status, "reason", reason)
+	return database.GetOrder(uuid)
+}
+
+// ForceRefund reject a validated order, refunding deducted assets and releasing its pending credit
+// A failed order whose deducted assets were not refunded yet is refunded and stays failed.
+func ForceRefund(uuid, operator, reason string) (*database.UUIDMapping, error) {
+	order, note, err := prepareOrderAction(uuid, "force refund", operator, reason, database.OrderStatusValidated, database.OrderStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == database.OrderStatusFailed {
+		order, err = database.RefundFailedOrder(uuid, note)
+		if err == nil {
+			ReleaseRuleUsage(uuid)
+		}
+	} else {
+		order, err = closeOrder(uuid, note)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Warn("ForceRefund", "audit", "operator_refund", "operator", operator, "uuid", uuid, "refunded", order.Deducted, "reason", reason)
+	return order, nil
+}
+
+// MarkSettled settle an order as a successful result without a receipt (e.g. confirmed by CROSS RAMP support)
+func MarkSettled(ctx context.Context, uuid, txHash, operator, reason string) (*database.UUIDMapping, error) {
+	order, note, err := prepareSettlement(uuid, "mark settled", operator, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := database.NoteOrder(uuid, note); err != nil {
+		return nil, err
+	}
+	if err := ApplyExchangeResult(ctx, uuid, txHash, *order.Intent, 1); err != nil {
+		return nil, err
+	}
+
+	slog.Warn("MarkSettled", "audit", "operator_settle", "operator", operator, "uuid", uuid, "txHash", txHash, "reason", reason)
+	return database.GetOrder(uuid)
+}
+
+// prepareSettlement check that an order can be settled by an operator (validated or expired, with its intent)
+func prepareSettlement(uuid, action, operator, reason string) (*database.UUIDMapping, string, error) {
+	order, note, err := prepareOrderAction(uuid, action, operator, reason, database.OrderStatusValidated, database.OrderStatusExpired)
+	if err != nil {
+		return nil, "", err
+	}
+	if order.Intent == nil {
+		return nil, "", fmt.Errorf("%w: %s", ErrNoValidatedIntent, uuid)
+	}
+	return order, note, nil
+}
+
+// prepareOrderAction check that an operator action applies to the order in its current status
+// and build the note recorded in the order's history
+func prepareOrderAction(uuid, action, operator, reason string, allowed ...string) (*database.UUIDMapping, string, error) {
+	reason = strings.TrimSpace(reason)
+	if operator == "" || reason == "" {
+		return nil, "", ErrReasonRequired
+	}
+
+	order, err := database.GetOrder(uuid)
+	if err != nil {
+		return nil, "", err
+	}
+	if order == nil {
+		return nil, "", fmt.Errorf("%w: %s", database.ErrOrderNotFound, uuid)
+	}
+	if !slices.Contains(allowed, order.Status) {
+		return nil, "", fmt.Errorf("%w: %s is %s", database.ErrOrderStatusStale, uuid, order.Status)
+	}
+
+	return order, fmt.Sprintf("%s by %s: %s", action, operator, reason), nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sample-game-backend/internal/database"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdjustBalance(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "admin-adjust"
+	before := moneyBalance(t, sessionID)
+
+	// 운영자 지급과 회수는 원장에 운영자와 사유가 기록됨
+	_, err := AdjustBalance(sessionID, "asset_money", 250, "alice", "compensation for ticket 42")
+	require.NoError(t, err)
+	session, err := AdjustBalance(sessionID, "asset_money", -50, "bob", "duplicate compensation")
+	require.NoError(t, err)
+	assert.Equal(t, before+200, moneyBalance(t, sessionID))
+
+	entries, err := database.ListLedger(sessionID)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, database.LedgerSourceAdjustment, entries[0].Source)
+	assert.Equal(t, int64(250), entries[0].Delta)
+	assert.Equal(t, "alice", entries[0].Operator)
+	assert.Equal(t, "compensation for ticket 42", entries[0].Reason)
+	assert.Equal(t, int64(-50), entries[1].Delta)
+	assert.Equal(t, "bob", entries[1].Operator)
+	assert.Equal(t, session.Assets["asset_money"], entries[1].Balance)
+}
+
+func TestAdjustBalanceRejected(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "admin-adjust-rejected"
+	before := moneyBalance(t, sessionID)
+
+	tests := []struct {
+		name      string
+		sessionID string
+		assetID   string
+		amount    int64
+		operator  string
+		reason    string
+		target    error
+	}{
+		{"zero amount", sessionID, "asset_money", 0, "alice", "reason", ErrInvalidAdjustment},
+		{"missing reason", sessionID, "asset_money", 10, "alice", " ", ErrInvalidAdjustment},
+		{"missing operator", sessionID, "asset_money", 10, "", "reason", ErrInvalidAdjustment},
+		{"unknown asset", sessionID, "asset_unknown", 10, "alice", "reason", ErrInvalidAdjustment},
+		{"unknown session", "admin-never-seen", "asset_money", 10, "alice", "reason", database.ErrSessionNotFound},
+		{"debit above balance", sessionID, "asset_money", -int64(before) - 1, "alice", "reason", database.ErrInsufficientBalance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AdjustBalance(tt.sessionID, tt.assetID, tt.amount, tt.operator, tt.reason)
+			assert.ErrorIs(t, err, tt.target)
+		})
+	}
+
+	// 거부된 조정은 잔액과 원장을 바꾸지 않고 세션도 만들지 않음
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+	entries, err := database.ListLedger(sessionID)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	session, err := database.GetSessionAssets("admin-never-seen")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+}
+
+// historyReasons 주문 이력의 사유 목록
+func historyReasons(order *database.UUIDMapping) []string {
+	var reasons []string
+	for _, event := range order.History {
+		reasons = append(reasons, event.Reason)
+	}
+	return reasons
+}
+
+func TestForceRefund(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "admin-force-refund"
+	before := moneyBalance(t, sessionID)
+	validateAssemble(t, "admin-refund", sessionID, 100)
+
+	_, err := ForceRefund("admin-refund", "alice", "")
+	assert.ErrorIs(t, err, ErrReasonRequired)
+
+	// 차감된 자산을 돌려주고 이력에 운영자와 사유를 남김
+	order, err := ForceRefund("admin-refund", "alice", "stuck after chain outage")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusRejected, order.Status)
+	assert.True(t, order.Refunded)
+	assert.Contains(t, historyReasons(order), "force refund by alice: stuck after chain outage")
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+
+	// 이미 종료된 주문은 다시 환불하지 않음
+	_, err = ForceRefund("admin-refund", "alice", "again")
+	assert.ErrorIs(t, err, database.ErrOrderStatusStale)
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+}
+
+func TestForceRefundFailedOrder(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "admin-force-refund-failed"
+	before := moneyBalance(t, sessionID)
+	validateAssemble(t, "admin-refund-failed", sessionID, 100)
+
+	// 환불 없이 실패로 기록된 주문
+	_, err := database.UpdateOrderStatus("admin-refund-failed", database.OrderStatusFailed, "test", database.OrderStatusValidated)
+	require.NoError(t, err)
+	require.Equal(t, before-100, moneyBalance(t, sessionID))
+
+	// 실패 상태를 유지한 채 차감된 자산을 돌려줌
+	order, err := ForceRefund("admin-refund-failed", "alice", "failed before refunds existed")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusFailed, order.Status)
+	assert.True(t, order.Refunded)
+	assert.Contains(t, historyReasons(order), "force refund by alice: failed before refunds existed")
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+
+	// 이미 환불된 주문은 다시 환불하지 않음
+	_, err = ForceRefund("admin-refund-failed", "alice", "again")
+	assert.ErrorIs(t, err, database.ErrOrderStatusStale)
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+}
+
+func TestMarkSettled(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "admin-mark-settled"
+	before := moneyBalance(t, sessionID)
+	validateDisassemble(t, "admin-settle", sessionID, disassembleMoney(300))
+
+	// 결과 웹훅과 같은 경로로 지급
+	order, err := MarkSettled(context.Background(), "admin-settle", "", "bob", "confirmed by CROSS RAMP support")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusSettled, order.Status)
+	assert.Contains(t, historyReasons(order), "mark settled by bob: confirmed by CROSS RAMP support")
+	assert.Equal(t, before+300, moneyBalance(t, sessionID))
+
+	_, err = MarkSettled(context.Background(), "admin-settle", "", "bob", "again")
+	assert.ErrorIs(t, err, database.ErrOrderStatusStale)
+	assert.Equal(t, before+300, moneyBalance(t, sessionID))
+}
+
+func TestRetrySettlement(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "admin-retry"
+	before := moneyBalance(t, sessionID)
+	validateDisassemble(t, "admin-retry", sessionID, disassembleMoney(200))
+
+	// 영수증이 없고 RPC도 설정되지 않으면 가져올 수 없음
+	_, err := RetrySettlement(context.Background(), "admin-retry", "", nil, "alice", "lost webhook")
+	assert.ErrorIs(t, err, ErrReceiptUnavailable)
+
+	txHash := common.HexToHash("0x0a0b")
+	receipt := &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful, TxHash: txHash}
+	order, err := RetrySettlement(context.Background(), "admin-retry", "", receipt, "alice", "lost webhook")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusSettled, order.Status)
+	assert.Equal(t, txHash.Hex(), order.TxHash)
+	assert.Contains(t, historyReasons(order), "retry by alice: lost webhook")
+	assert.Equal(t, before+200, moneyBalance(t, sessionID))
+
+	_, err = RetrySettlement(context.Background(), "unknown-order", "", receipt, "alice", "lost webhook")
+	assert.ErrorIs(t, err, database.ErrOrderNotFound)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"sample-game-backend/internal/audit"
+	"sample-game-backend/internal/config"
+)
+
+var (
+	// signatureAudit hash-chained log of issued validator signatures (nil disables auditing)
+	signatureAudit *audit.Log
+	auditMu        sync.RWMutex
+)
+
+// InitAudit open the validator signature audit log
+func InitAudit(cfg config.AuditConfig) error {
+	if cfg.Path == "" {
+		slog.Warn("InitAudit", "warning", "No audit log configured, validator signatures are not audited", "env", "AUDIT_LOG_PATH")
+		return nil
+	}
+
+	log, err := audit.Open(cfg.Path)
+	if err != nil {
+		return err
+	}
+	SetSignatureAudit(log)
+	slog.Info("InitAudit", "path", cfg.Path, "signer", keystoreService.Address().Hex())
+	return nil
+}
+
+// SetSignatureAudit replace the signature audit log (nil disables auditing)
+func SetSignatureAudit(log *audit.Log) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	signatureAudit = log
+}
+
+// CloseAudit close the signature audit log
+func CloseAudit() error {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if signatureAudit == nil {
+		return nil
+	}
+	err := signatureAudit.Close()
+	signatureAudit = nil
+	return err
+}
+
+// AuditSignature append an issued validator signature to the audit log
+// The signature must not be returned when this fails.
+func AuditSignature(record audit.Record) error {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+	if signatureAudit == nil {
+		return nil
+	}
+
+	record.Signer = keystoreService.Address().Hex()
+	entry, err := signatureAudit.Append(record)
+	if err != nil {
+		return fmt.Errorf("audit validator signature: %w", err)
+	}
+	slog.Info("AuditSignature", "uuid", record.UUID, "seq", entry.Seq, "hash", entry.Hash)
+	return nil
+}
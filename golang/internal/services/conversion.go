@@ -0,0 +1,46 @@
+package services
+
+import (
+	"log/slog"
+	"sync"
+
+	"sample-game-backend/internal/conversion"
+	"sample-game-backend/internal/models"
+)
+
+var (
+	// conversionTable registered conversion rules (intents are not rate-checked when nil)
+	conversionTable *conversion.Table
+	conversionMu    sync.RWMutex
+)
+
+// InitConversions load conversion table from file
+func InitConversions(path string) error {
+	table, err := conversion.Load(path)
+	if err != nil {
+		slog.Error("InitConversions", "error", "Failed to load conversion table", "err", err, "path", path)
+		return err
+	}
+	SetConversionTable(table)
+	slog.Info("InitConversions", "status", "success", "path", path, "rules", len(table.Rules))
+	return nil
+}
+
+// SetConversionTable replace conversion table (nil disables the check)
+func SetConversionTable(table *conversion.Table) {
+	conversionMu.Lock()
+	defer conversionMu.Unlock()
+	conversionTable = table
+}
+
+// ValidateConversion check intent amounts against the conversion table
+// Returns *conversion.Mismatch when the intent does not follow a registered rule
+func ValidateConversion(intent models.ExchangeIntent) error {
+	conversionMu.RLock()
+	table := conversionTable
+	conversionMu.RUnlock()
+	if table == nil {
+		return nil
+	}
+	return table.Validate(intent)
+}
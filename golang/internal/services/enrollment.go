@@ -0,0 +1,300 @@
+package services
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/database"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Enrollment methods
+const (
+	EnrollmentMethodSignature = "signature"
+	EnrollmentMethodJWT       = "jwt"
+	EnrollmentMethodOperator  = "operator"
+)
+
+// enrollmentMessageTitle first line of the message signed for enrollment
+const enrollmentMessageTitle = "CROSS RAMP wallet enrollment"
+
+// jwtWalletClaim CROSS_AUTH_JWT claim holding the wallet address (sub holds the session ID)
+const jwtWalletClaim = "wallet_address"
+
+// Enrollment errors
+var (
+	ErrWalletNotEnrolled         = errors.New("wallet not enrolled")
+	ErrWalletMismatch            = errors.New("user address does not match enrolled wallet")
+	ErrEnrollmentVerification    = errors.New("wallet ownership verification failed")
+	ErrEnrollmentJWTNotSupported = errors.New("CROSS_AUTH_JWT verification is not configured")
+)
+
+var (
+	enrollmentConfig = config.EnrollmentConfig{MessageTTL: 10 * time.Minute}
+	jwtVerifyKey     any
+	enrollmentMu     sync.RWMutex
+)
+
+// InitEnrollment configure wallet enrollment
+func InitEnrollment(cfg config.EnrollmentConfig) error {
+	var key any
+	switch {
+	case cfg.JWTPublicKeyPath != "":
+		pemBytes, err := os.ReadFile(cfg.JWTPublicKeyPath)
+		if err != nil {
+			return fmt.Errorf("failed to read JWT public key: %w", err)
+		}
+		key, err = parsePublicKeyPEM(pemBytes)
+		if err != nil {
+			return err
+		}
+	case cfg.JWTSecret != "":
+		key = []byte(cfg.JWTSecret)
+	}
+	if key != nil && (cfg.JWTIssuer == "" || cfg.JWTAudience == "") {
+		return errors.New("CROSS_AUTH_JWT verification needs an issuer and an audience")
+	}
+
+	if cfg.MessageTTL <= 0 {
+		cfg.MessageTTL = 10 * time.Minute
+	}
+
+	enrollmentMu.Lock()
+	defer enrollmentMu.Unlock()
+	enrollmentConfig = cfg
+	jwtVerifyKey = key
+	return nil
+}
+
+// parsePublicKeyPEM parse PKIX public key (RSA or ECDSA)
+func parsePublicKeyPEM(pemBytes []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("invalid JWT public key: no PEM block found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT public key: %w", err)
+	}
+	return key, nil
+}
+
+// BuildEnrollmentMessage build the message a wallet signs to enroll with a session
+func BuildEnrollmentMessage(sessionID string, wallet common.Address, issuedAt time.Time) string {
+	return strings.Join([]string{
+		enrollmentMessageTitle,
+		"Session: " + sessionID,
+		"Wallet: " + wallet.Hex(),
+		"Issued At: " + issuedAt.UTC().Format(time.RFC3339),
+	}, "\n")
+}
+
+// VerifyEnrollmentSignature verify personal_sign signature over the enrollment message
+// The message must name the session and wallet and must not be older than the configured TTL
+func VerifyEnrollmentSignature(sessionID, walletAddress, message, signature string) (common.Address, error) {
+	if !common.IsHexAddress(walletAddress) {
+		return common.Address{}, fmt.Errorf("%w: invalid wallet address", ErrEnrollmentVerification)
+	}
+	wallet := common.HexToAddress(walletAddress)
+
+	signer, err := recoverEnrollmentSigner(sessionID, wallet, message, signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if signer != wallet {
+		return common.Address{}, fmt.Errorf("%w: signer %s does not match wallet", ErrEnrollmentVerification, signer.Hex())
+	}
+
+	return wallet, nil
+}
+
+// VerifyWalletChange verify that the session's enrolled wallet signed the enrollment message of the new wallet
+// Returns the enrolled wallet, which the new enrollment may then replace.
+func VerifyWalletChange(sessionID string, wallet common.Address, message, signature string) (common.Address, error) {
+	enrollment, err := database.GetWalletEnrollment(sessionID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if enrollment == nil {
+		return common.Address{}, ErrWalletNotEnrolled
+	}
+
+	signer, err := recoverEnrollmentSigner(sessionID, wallet, message, signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	current := common.HexToAddress(enrollment.WalletAddress)
+	if signer != current {
+		return common.Address{}, fmt.Errorf("%w: change is not signed by the enrolled wallet", ErrEnrollmentVerification)
+	}
+
+	return current, nil
+}
+
+// recoverEnrollmentSigner check that the enrollment message names the session and wallet and is not older than
+// the configured TTL, then recover the address that signed it with personal_sign
+func recoverEnrollmentSigner(sessionID string, wallet common.Address, message, signature string) (common.Address, error) {
+	fields := parseEnrollmentMessage(message)
+	if fields["title"] != enrollmentMessageTitle || fields["Session"] != sessionID {
+		return common.Address{}, fmt.Errorf("%w: message is not for this session", ErrEnrollmentVerification)
+	}
+	if !strings.EqualFold(fields["Wallet"], wallet.Hex()) {
+		return common.Address{}, fmt.Errorf("%w: message is not for this wallet", ErrEnrollmentVerification)
+	}
+
+	issuedAt, err := time.Parse(time.RFC3339, fields["Issued At"])
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: invalid issued at", ErrEnrollmentVerification)
+	}
+	enrollmentMu.RLock()
+	ttl := enrollmentConfig.MessageTTL
+	enrollmentMu.RUnlock()
+	if age := time.Since(issuedAt); age > ttl || age < -time.Minute {
+		return common.Address{}, fmt.Errorf("%w: message expired", ErrEnrollmentVerification)
+	}
+
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != ethcrypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: invalid signature format", ErrEnrollmentVerification)
+	}
+	// Wallets return V as 27/28
+	if sig[ethcrypto.RecoveryIDOffset] >= 27 {
+		sig[ethcrypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := ethcrypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrEnrollmentVerification, err)
+	}
+	return ethcrypto.PubkeyToAddress(*pubKey), nil
+}
+
+// parseEnrollmentMessage split enrollment message into "Key: value" fields
+func parseEnrollmentMessage(message string) map[string]string {
+	fields := make(map[string]string)
+	for i, line := range strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n") {
+		if i == 0 {
+			fields["title"] = strings.TrimSpace(line)
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+// VerifyCrossAuthJWT verify CROSS_AUTH_JWT and return the wallet address in its wallet_address claim
+// The token must be issued for the session (sub) by the configured issuer for the configured audience.
+func VerifyCrossAuthJWT(sessionID, authorization string) (common.Address, error) {
+	enrollmentMu.RLock()
+	key, issuer, audience := jwtVerifyKey, enrollmentConfig.JWTIssuer, enrollmentConfig.JWTAudience
+	enrollmentMu.RUnlock()
+	if key == nil {
+		return common.Address{}, ErrEnrollmentJWTNotSupported
+	}
+
+	tokenString := strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	if tokenString == "" {
+		return common.Address{}, fmt.Errorf("%w: missing token", ErrEnrollmentVerification)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if _, ok := key.([]byte); ok {
+				return key, nil
+			}
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+			if _, ok := key.([]byte); !ok {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}, jwt.WithExpirationRequired(), jwt.WithSubject(sessionID), jwt.WithIssuer(issuer), jwt.WithAudience(audience))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrEnrollmentVerification, err)
+	}
+
+	wallet, ok := claims[jwtWalletClaim].(string)
+	if !ok || !common.IsHexAddress(wallet) {
+		return common.Address{}, fmt.Errorf("%w: %s is not a wallet address", ErrEnrollmentVerification, jwtWalletClaim)
+	}
+
+	return common.HexToAddress(wallet), nil
+}
+
+// EnrollWallet link verified wallet to session
+// Ownership is already proven, so the session is admitted even when only known sessions are created.
+// A different enrolled wallet is only replaced when replaces names it (see VerifyWalletChange).
+func EnrollWallet(sessionID string, wallet common.Address, method, replaces string) (*database.WalletEnrollment, error) {
+	// Enrollment requires a known player
+	if _, err := database.AdmitSessionAssets(sessionID); err != nil {
+		return nil, err
+	}
+	return database.StoreWalletEnrollment(sessionID, wallet.Hex(), method, replaces)
+}
+
+// ReplaceEnrollment enroll a wallet with a session on behalf of an operator, replacing any enrolled wallet
+func ReplaceEnrollment(sessionID string, wallet common.Address, operator, reason string) (*database.WalletEnrollment, error) {
+	reason = strings.TrimSpace(reason)
+	if operator == "" || reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	current, err := database.GetWalletEnrollment(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var replaces string
+	if current != nil {
+		replaces = current.WalletAddress
+	}
+
+	enrollment, err := database.StoreWalletEnrollment(sessionID, wallet.Hex(), EnrollmentMethodOperator, replaces)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Warn("ReplaceEnrollment", "audit", "operator_enrollment", "operator", operator, "sessionID", sessionID, "previousWallet", replaces, "walletAddress", enrollment.WalletAddress, "reason", reason)
+	return enrollment, nil
+}
+
+// CheckEnrolledWallet ensure user address matches the session's enrolled wallet
+// Only enforced when enrollment is required by configuration
+func CheckEnrolledWallet(sessionID, userAddress string) error {
+	enrollmentMu.RLock()
+	required := enrollmentConfig.Required
+	enrollmentMu.RUnlock()
+	if !required {
+		return nil
+	}
+
+	enrollment, err := database.GetWalletEnrollment(sessionID)
+	if err != nil {
+		return err
+	}
+	if enrollment == nil {
+		return ErrWalletNotEnrolled
+	}
+	if !common.IsHexAddress(userAddress) || common.HexToAddress(userAddress) != common.HexToAddress(enrollment.WalletAddress) {
+		return ErrWalletMismatch
+	}
+
+	return nil
+}
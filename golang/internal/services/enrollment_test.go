@@ -0,0 +1,223 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/database"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// personalSign 지갑의 personal_sign 동작을 재현
+func personalSign(t *testing.T, message string) (string, string) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+
+	return crypto.PubkeyToAddress(key.PublicKey).Hex(), hexutil.Encode(sig)
+}
+
+func TestVerifyEnrollmentSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := crypto.PubkeyToAddress(key.PublicKey)
+
+	sign := func(message string) string {
+		sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+		require.NoError(t, err)
+		sig[crypto.RecoveryIDOffset] += 27
+		return hexutil.Encode(sig)
+	}
+
+	// 정상 서명
+	message := BuildEnrollmentMessage("session-1", wallet, time.Now())
+	recovered, err := VerifyEnrollmentSignature("session-1", wallet.Hex(), message, sign(message))
+	require.NoError(t, err)
+	assert.Equal(t, wallet, recovered)
+
+	// 다른 세션용 메시지는 거부
+	_, err = VerifyEnrollmentSignature("session-2", wallet.Hex(), message, sign(message))
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+
+	// 만료된 메시지는 거부
+	expired := BuildEnrollmentMessage("session-1", wallet, time.Now().Add(-time.Hour))
+	_, err = VerifyEnrollmentSignature("session-1", wallet.Hex(), expired, sign(expired))
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+
+	// 다른 지갑이 서명한 경우 거부
+	otherWallet, otherSig := personalSign(t, message)
+	assert.NotEqual(t, wallet.Hex(), otherWallet)
+	_, err = VerifyEnrollmentSignature("session-1", wallet.Hex(), message, otherSig)
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+}
+
+func TestVerifyCrossAuthJWT(t *testing.T) {
+	secret := "test-jwt-secret"
+	wallet := "0xB777C937fa1afC99606aFa85c5b83cFe7f82BabD"
+	cfg := config.EnrollmentConfig{JWTSecret: secret, JWTIssuer: "cross-auth", JWTAudience: "sample-game"}
+
+	// 검증 키가 없으면 JWT 등록은 지원되지 않음
+	require.NoError(t, InitEnrollment(config.EnrollmentConfig{}))
+	_, err := VerifyCrossAuthJWT("jwt-session", "Bearer token")
+	assert.ErrorIs(t, err, ErrEnrollmentJWTNotSupported)
+
+	// 발급자와 대상 없이 검증 키만 설정할 수 없음
+	assert.Error(t, InitEnrollment(config.EnrollmentConfig{JWTSecret: secret}))
+
+	require.NoError(t, InitEnrollment(cfg))
+	defer InitEnrollment(config.EnrollmentConfig{})
+
+	sign := func(key string, claims jwt.MapClaims) string {
+		token := jwt.MapClaims{
+			"sub":            "jwt-session",
+			"iss":            "cross-auth",
+			"aud":            "sample-game",
+			"wallet_address": wallet,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+		}
+		for name, value := range claims {
+			token[name] = value
+		}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, token).SignedString([]byte(key))
+		require.NoError(t, err)
+		return "Bearer " + signed
+	}
+
+	address, err := VerifyCrossAuthJWT("jwt-session", sign(secret, nil))
+	require.NoError(t, err)
+	assert.Equal(t, wallet, address.Hex())
+
+	// 잘못된 키로 서명된 토큰은 거부
+	_, err = VerifyCrossAuthJWT("jwt-session", sign("other-secret", nil))
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+
+	// 다른 세션, 발급자, 대상용 토큰과 지갑 주소가 없는 토큰은 거부
+	_, err = VerifyCrossAuthJWT("other-session", sign(secret, nil))
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+	_, err = VerifyCrossAuthJWT("jwt-session", sign(secret, jwt.MapClaims{"iss": "other-issuer"}))
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+	_, err = VerifyCrossAuthJWT("jwt-session", sign(secret, jwt.MapClaims{"aud": "other-game"}))
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+	_, err = VerifyCrossAuthJWT("jwt-session", sign(secret, jwt.MapClaims{"wallet_address": "jwt-session"}))
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+}
+
+func TestCheckEnrolledWallet(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	wallet := "0xB777C937fa1afC99606aFa85c5b83cFe7f82BabD"
+
+	// 등록이 필수가 아니면 검사하지 않음
+	require.NoError(t, InitEnrollment(config.EnrollmentConfig{}))
+	assert.NoError(t, CheckEnrolledWallet("enroll-session", "0x0000000000000000000000000000000000000001"))
+
+	require.NoError(t, InitEnrollment(config.EnrollmentConfig{Required: true}))
+	defer InitEnrollment(config.EnrollmentConfig{})
+
+	assert.ErrorIs(t, CheckEnrolledWallet("enroll-session", wallet), ErrWalletNotEnrolled)
+
+	_, err := database.StoreWalletEnrollment("enroll-session", wallet, EnrollmentMethodSignature, "")
+	require.NoError(t, err)
+
+	assert.NoError(t, CheckEnrolledWallet("enroll-session", "0xb777c937fa1afc99606afa85c5b83cfe7f82babd"), "address comparison should ignore case")
+	assert.ErrorIs(t, CheckEnrolledWallet("enroll-session", "0x0000000000000000000000000000000000000001"), ErrWalletMismatch)
+}
+
+func TestEnrollWalletAdmitsUnknownSession(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	// 알려진 세션만 생성하는 정책 (데모 제공자는 어떤 세션도 인식하지 않음)
+	database.SetSessionPolicy(database.SessionPolicy{RequireKnown: true})
+	defer database.SetSessionPolicy(database.SessionPolicy{})
+
+	sessionID := "enroll-require-known"
+	_, err := database.GetOrCreateSessionAssets(sessionID)
+	require.ErrorIs(t, err, database.ErrSessionNotRecognized)
+
+	// 지갑 소유를 증명하면 세션을 생성하고 등록
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := crypto.PubkeyToAddress(key.PublicKey)
+	message := BuildEnrollmentMessage(sessionID, wallet, time.Now())
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+
+	verified, err := VerifyEnrollmentSignature(sessionID, wallet.Hex(), message, hexutil.Encode(sig))
+	require.NoError(t, err)
+	enrollment, err := EnrollWallet(sessionID, verified, EnrollmentMethodSignature, "")
+	require.NoError(t, err)
+	assert.Equal(t, wallet.Hex(), enrollment.WalletAddress)
+
+	// 이후 assets/validate 경로에서도 세션 사용 가능
+	session, err := database.GetOrCreateSessionAssets(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, session.SessionID)
+}
+
+func TestEnrollWalletReplacement(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "enroll-replace"
+	newWallet := func() (*ecdsa.PrivateKey, common.Address) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		return key, crypto.PubkeyToAddress(key.PublicKey)
+	}
+	sign := func(key *ecdsa.PrivateKey, message string) string {
+		sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+		require.NoError(t, err)
+		sig[crypto.RecoveryIDOffset] += 27
+		return hexutil.Encode(sig)
+	}
+
+	currentKey, current := newWallet()
+	_, err := EnrollWallet(sessionID, current, EnrollmentMethodSignature, "")
+	require.NoError(t, err)
+
+	// 같은 지갑은 다시 등록 가능
+	_, err = EnrollWallet(sessionID, current, EnrollmentMethodSignature, "")
+	require.NoError(t, err)
+
+	// 세션 ID만 아는 다른 지갑은 기존 등록을 덮어쓸 수 없음
+	otherKey, other := newWallet()
+	_, err = EnrollWallet(sessionID, other, EnrollmentMethodSignature, "")
+	assert.ErrorIs(t, err, database.ErrWalletAlreadyEnrolled)
+
+	// 새 지갑이 자신의 변경을 승인할 수는 없음
+	message := BuildEnrollmentMessage(sessionID, other, time.Now())
+	_, err = VerifyWalletChange(sessionID, other, message, sign(otherKey, message))
+	assert.ErrorIs(t, err, ErrEnrollmentVerification)
+
+	// 현재 지갑이 서명하면 변경
+	replaces, err := VerifyWalletChange(sessionID, other, message, sign(currentKey, message))
+	require.NoError(t, err)
+	assert.Equal(t, current, replaces)
+	enrollment, err := EnrollWallet(sessionID, other, EnrollmentMethodSignature, replaces.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, other.Hex(), enrollment.WalletAddress)
+
+	// 운영자 승인으로 변경 (사유 필수)
+	_, third := newWallet()
+	_, err = ReplaceEnrollment(sessionID, third, "alice", "")
+	assert.ErrorIs(t, err, ErrReasonRequired)
+	enrollment, err = ReplaceEnrollment(sessionID, third, "alice", "lost wallet, verified by support")
+	require.NoError(t, err)
+	assert.Equal(t, third.Hex(), enrollment.WalletAddress)
+	assert.Equal(t, EnrollmentMethodOperator, enrollment.Method)
+}
@@ -1,17 +1,34 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"sample-game-backend/internal/database"
 	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ProcessExchangeResult process exchange result
-func ProcessExchangeResult(sessionID string, outputs []models.PairAsset, receiptStatus uint64) error {
+// A pending credit reserved at validate is confirmed on success and released on failure
+func ProcessExchangeResult(uuid, sessionID string, outputs []models.PairAsset, receiptStatus uint64) error {
+	reservation, err := database.GetReservation(uuid)
+	if err != nil {
+		slog.Error("ProcessExchangeResult", "error", "Failed to get reservation", "err", err, "uuid", uuid)
+		return err
+	}
+
 	// Skip processing if receipt status is not 0x1
 	if receiptStatus != 1 {
-		slog.Info("ProcessExchangeResult", "sessionID", sessionID, "receiptStatus", receiptStatus, "action", "skipped")
+		if reservation != nil && reservation.Status == database.ReservationPending {
+			ReleaseCredit(uuid, database.ReservationReleased)
+		}
+		slog.Info("ProcessExchangeResult", "sessionID", sessionID, "uuid", uuid, "receiptStatus", receiptStatus, "action", "skipped")
 		return nil
 	}
 
@@ -21,13 +38,128 @@ func ProcessExchangeResult(sessionID string, outputs []models.PairAsset, receipt
 		return nil
 	}
 
-	// Process asset increase
-	err := database.AddAssets(sessionID, outputs)
-	if err != nil {
-		slog.Error("ProcessExchangeResult", "error", "Failed to add assets", "err", err, "sessionID", sessionID)
+	// Orders validated without a reservation are credited directly
+	if reservation == nil {
+		if err := database.AddAssets(sessionID, outputs, database.LedgerRef{Source: database.LedgerSourceResult, Reference: uuid}); err != nil {
+			slog.Error("ProcessExchangeResult", "error", "Failed to add assets", "err", err, "sessionID", sessionID)
+			return err
+		}
+		slog.Info("ProcessExchangeResult", "sessionID", sessionID, "outputs", outputs, "action", "assets_added")
+		return nil
+	}
+
+	// Confirm reservation and credit assets (repeated webhooks are not credited twice)
+	if _, err := database.ConfirmReservation(uuid, outputs); err != nil {
+		if errors.Is(err, database.ErrReservationNotPending) {
+			slog.Warn("ProcessExchangeResult", "warning", "Reservation already settled", "uuid", uuid, "status", reservation.Status, "action", "skipped")
+			return nil
+		}
+		slog.Error("ProcessExchangeResult", "error", "Failed to confirm reservation", "err", err, "uuid", uuid, "sessionID", sessionID)
 		return err
 	}
+	if reservation.Status == database.ReservationExpired {
+		slog.Warn("ProcessExchangeResult", "warning", "Result received after reservation expired", "uuid", uuid, "sessionID", sessionID)
+	}
 
-	slog.Info("ProcessExchangeResult", "sessionID", sessionID, "outputs", outputs, "action", "assets_added")
+	slog.Info("ProcessExchangeResult", "sessionID", sessionID, "uuid", uuid, "outputs", outputs, "action", "reservation_confirmed")
 	return nil
 }
+
+// ErrIntentMismatch result intent differs from the intent validated for the order
+var ErrIntentMismatch = errors.New("intent differs from the validated intent")
+
+// FlagIntentMismatch order flag recorded when a result's intent differs from the validated intent
+const FlagIntentMismatch = "intent_mismatch"
+
+// ProcessResult verify the result's receipt and apply it to the order
+// Shared by the result webhook and operator retries so both settle orders the same way.
+func ProcessResult(ctx context.Context, req models.ExchangeReq) error {
+	if err := VerifyResultReceipt(ctx, req); err != nil {
+		return err
+	}
+	return ApplyExchangeResult(ctx, req.UUID, req.TxHash.Hex(), req.Intent, uint64(req.Receipt.Status))
+}
+
+// ApplyExchangeResult apply the result webhook of an order
+// The posted intent must equal the intent stored at validate; a differing result is flagged and rejected
+func ApplyExchangeResult(ctx context.Context, uuid, txHash string, intent models.ExchangeIntent, receiptStatus uint64) (err error) {
+	_, span := tracing.Start(ctx, "ApplyExchangeResult", attribute.String("uuid", uuid), attribute.Int64("receipt.status", int64(receiptStatus)))
+	defer func() { tracing.End(span, err) }()
+
+	order, err := database.GetOrder(uuid)
+	if err != nil {
+		return err
+	}
+	if order != nil && order.Intent != nil {
+		if diff := intentDifference(*order.Intent, intent); diff != "" {
+			if _, err := database.FlagOrder(uuid, FlagIntentMismatch, diff); err != nil {
+				slog.Error("ApplyExchangeResult", "error", "Failed to flag order", "err", err, "uuid", uuid)
+			}
+			return fmt.Errorf("%w: %s", ErrIntentMismatch, diff)
+		}
+	}
+
+	return SettleOrder(uuid, txHash, intent, receiptStatus)
+}
+
+// SettleOrder settle an order with the intent stored at validate
+// Shared by the result webhook and reconciliation so both settle orders the same way.
+// fallback is only used for orders stored without an intent.
+func SettleOrder(uuid, txHash string, fallback models.ExchangeIntent, receiptStatus uint64) error {
+	order, err := database.GetOrder(uuid)
+	if err != nil {
+		return err
+	}
+	if order != nil && txHash != "" {
+		if err := database.SetOrderTxHash(uuid, txHash); err != nil {
+			return err
+		}
+	}
+	intent := fallback
+	if order != nil && order.Intent != nil {
+		intent = *order.Intent
+	}
+
+	if intent.Type == models.IntentTypeDisassemble && len(intent.To) > 0 {
+		if order == nil {
+			return fmt.Errorf("%w: %s", database.ErrOrderNotFound, uuid)
+		}
+
+		// Process exchange result
+		if err := ProcessExchangeResult(uuid, order.SessionID, intent.To, receiptStatus); err != nil {
+			return err
+		}
+	}
+
+	// Record result on the order
+	return CompleteOrder(uuid, receiptStatus)
+}
+
+// intentDifference describe how the posted intent differs from the validated one (empty if equal)
+// Types and asset IDs are compared case-insensitively; pair order must match.
+func intentDifference(validated, posted models.ExchangeIntent) string {
+	if !strings.EqualFold(validated.Type, posted.Type) {
+		return fmt.Sprintf("type %q, validated %q", posted.Type, validated.Type)
+	}
+	if !strings.EqualFold(validated.Method, posted.Method) {
+		return fmt.Sprintf("method %q, validated %q", posted.Method, validated.Method)
+	}
+	if diff := pairsDifference("from", validated.From, posted.From); diff != "" {
+		return diff
+	}
+	return pairsDifference("to", validated.To, posted.To)
+}
+
+// pairsDifference describe how posted pairs differ from the validated ones (empty if equal)
+func pairsDifference(side string, validated, posted []models.PairAsset) string {
+	if len(validated) != len(posted) {
+		return fmt.Sprintf("%s has %d pairs, validated %d", side, len(posted), len(validated))
+	}
+	for i := range validated {
+		v, p := validated[i], posted[i]
+		if !strings.EqualFold(v.Type, p.Type) || !strings.EqualFold(v.AssetID, p.AssetID) || v.Amount != p.Amount {
+			return fmt.Sprintf("%s[%d] is %s %s x%d, validated %s %s x%d", side, i, p.Type, p.AssetID, p.Amount, v.Type, v.AssetID, v.Amount)
+		}
+	}
+	return ""
+}
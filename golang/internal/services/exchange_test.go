@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validateDisassemble validate 경로처럼 검증된 intent와 함께 주문 저장 후 크레딧 예약
+func validateDisassemble(t *testing.T, uuid, sessionID string, intent models.ExchangeIntent) {
+	require.NoError(t, ReserveRuleUsage("project", sessionID, uuid, intent))
+	require.NoError(t, database.StoreOrder(&database.UUIDMapping{
+		UUID:       uuid,
+		SessionID:  sessionID,
+		ProjectID:  "project",
+		IntentType: intent.Type,
+		Intent:     &intent,
+	}))
+	require.NoError(t, ReserveCredit("project", sessionID, uuid, intent))
+}
+
+func TestApplyExchangeResultIntentMismatch(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "exchange-intent-mismatch"
+	before := moneyBalance(t, sessionID)
+	validateDisassemble(t, "exchange-tampered", sessionID, disassembleMoney(100))
+
+	// 검증된 intent와 다른 결과는 거부되고 주문에 표시됨
+	tampered := disassembleMoney(100000)
+	err := ApplyExchangeResult(context.Background(), "exchange-tampered", "", tampered, 1)
+	assert.ErrorIs(t, err, ErrIntentMismatch)
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+
+	order, err := database.GetOrder("exchange-tampered")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusValidated, order.Status)
+	assert.Equal(t, []string{FlagIntentMismatch}, order.Flags)
+
+	// 대소문자만 다른 intent는 같은 것으로 처리
+	posted := disassembleMoney(100)
+	posted.Type = "Disassemble"
+	posted.From[0].Type = "ERC20"
+	require.NoError(t, ApplyExchangeResult(context.Background(), "exchange-tampered", "", posted, 1))
+	assert.Equal(t, before+100, moneyBalance(t, sessionID))
+}
+
+func TestSettleOrderUsesStoredIntent(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "exchange-stored-intent"
+	before := moneyBalance(t, sessionID)
+	validateDisassemble(t, "exchange-stored", sessionID, disassembleMoney(300))
+
+	// 조정 작업이 전달한 intent가 달라도 저장된 intent로 지급
+	require.NoError(t, SettleOrder("exchange-stored", "0xABCDEF0000000000000000000000000000000000000000000000000000000001", disassembleMoney(5000), 1))
+	assert.Equal(t, before+300, moneyBalance(t, sessionID))
+
+	order, err := database.GetOrder("exchange-stored")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusSettled, order.Status)
+
+	// 결과의 tx_hash로 주문 조회 (대소문자 무시)
+	found, err := database.GetOrderByTxHash("0xabcdef0000000000000000000000000000000000000000000000000000000001")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "exchange-stored", found.UUID)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSignerNotLoaded validator signing key is not loaded
+var ErrSignerNotLoaded = errors.New("validator signer not loaded")
+
+// CheckSigner check the validator key is loaded
+// Readiness probes run often, so the key is not exercised by signing.
+func CheckSigner(context.Context) error {
+	if keystoreService == nil || keystoreService.key == nil || keystoreService.key.D == nil || keystoreService.key.D.Sign() == 0 {
+		return ErrSignerNotLoaded
+	}
+	return nil
+}
+
+// CheckAudit check the signature audit log can still be written (nil when auditing is disabled)
+func CheckAudit(context.Context) error {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+	if signatureAudit == nil {
+		return nil
+	}
+	return signatureAudit.Check()
+}
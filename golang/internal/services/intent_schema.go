@@ -0,0 +1,139 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Pair asset types
+const (
+	PairAssetTypeAsset   = "asset"
+	PairAssetTypeERC20   = "erc20"
+	PairAssetTypeERC721  = "erc721"
+	PairAssetTypeERC1155 = "erc1155"
+)
+
+// intentSchema allowed methods and pair asset types of an intent type
+type intentSchema struct {
+	Methods   []string
+	FromTypes []string
+	ToTypes   []string
+}
+
+// tokenTypes pair asset types identified by a contract address
+var tokenTypes = []string{PairAssetTypeERC20, PairAssetTypeERC721, PairAssetTypeERC1155}
+
+// intentSchemas schema per intent type
+// assemble converts in-game assets into tokens, disassemble converts tokens into in-game assets
+var intentSchemas = map[string]intentSchema{
+	models.IntentTypeAssemble: {
+		Methods:   []string{"mint", "transfer"},
+		FromTypes: []string{PairAssetTypeAsset},
+		ToTypes:   tokenTypes,
+	},
+	models.IntentTypeDisassemble: {
+		Methods:   []string{"burn", "burn-permit", "transfer", "transfer-from", "transfer-from-permit"},
+		FromTypes: tokenTypes,
+		ToTypes:   []string{PairAssetTypeAsset},
+	},
+}
+
+// ValidateRequestFields validate request fields and intent schema
+// Returns field-level errors (empty when the request is valid)
+func ValidateRequestFields(req models.ValidateRequest) []models.FieldError {
+	var errs []models.FieldError
+
+	if !isHexAddress(req.UserAddress) {
+		errs = append(errs, models.FieldError{Field: "user_address", Reason: "must be a 0x-prefixed 20-byte hex address"})
+	}
+	if digest, err := hexutil.Decode(req.Digest); err != nil || len(digest) != common.HashLength {
+		errs = append(errs, models.FieldError{Field: "digest", Reason: "must be a 0x-prefixed 32-byte hex value"})
+	}
+	if sig, err := hexutil.Decode(req.UserSig); err != nil || len(sig) == 0 {
+		errs = append(errs, models.FieldError{Field: "user_sig", Reason: "must be 0x-prefixed hex"})
+	}
+
+	return append(errs, ValidateIntent(req.Intent)...)
+}
+
+// ValidateIntent validate intent against the schema of its type
+// Returns field-level errors (empty when the intent is valid)
+func ValidateIntent(intent models.ExchangeIntent) []models.FieldError {
+	schema, ok := intentSchemas[intent.Type]
+	if !ok {
+		return []models.FieldError{{Field: "intent.type", Reason: "must be one of assemble, disassemble"}}
+	}
+
+	var errs []models.FieldError
+	if !contains(schema.Methods, intent.Method) {
+		errs = append(errs, models.FieldError{
+			Field:  "intent.method",
+			Reason: fmt.Sprintf("must be one of %s for %s", strings.Join(schema.Methods, ", "), intent.Type),
+		})
+	}
+
+	errs = append(errs, validatePairAssets("intent.from", intent.From, schema.FromTypes)...)
+	errs = append(errs, validatePairAssets("intent.to", intent.To, schema.ToTypes)...)
+	return errs
+}
+
+// validatePairAssets validate one side of an intent
+func validatePairAssets(field string, assets []models.PairAsset, allowedTypes []string) []models.FieldError {
+	if len(assets) == 0 {
+		return []models.FieldError{{Field: field, Reason: "must contain at least one asset"}}
+	}
+
+	var errs []models.FieldError
+	seen := make(map[string]bool, len(assets))
+	for i, asset := range assets {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+
+		if !contains(allowedTypes, asset.Type) {
+			errs = append(errs, models.FieldError{
+				Field:  itemField + ".type",
+				Reason: fmt.Sprintf("must be one of %s", strings.Join(allowedTypes, ", ")),
+			})
+		}
+
+		switch {
+		case asset.AssetID == "":
+			errs = append(errs, models.FieldError{Field: itemField + ".id", Reason: "must not be empty"})
+		case contains(tokenTypes, asset.Type) && !isHexAddress(asset.AssetID):
+			errs = append(errs, models.FieldError{Field: itemField + ".id", Reason: "must be a 0x-prefixed 20-byte contract address"})
+		case seen[strings.ToLower(asset.AssetID)]:
+			errs = append(errs, models.FieldError{Field: itemField + ".id", Reason: "duplicate asset id"})
+		}
+		seen[strings.ToLower(asset.AssetID)] = true
+
+		switch {
+		case asset.Amount == 0:
+			errs = append(errs, models.FieldError{Field: itemField + ".amount", Reason: "must be greater than zero"})
+		case uint64(asset.Amount) > math.MaxInt64:
+			errs = append(errs, models.FieldError{Field: itemField + ".amount", Reason: fmt.Sprintf("must not exceed %d", int64(math.MaxInt64))})
+		}
+	}
+
+	return errs
+}
+
+// isHexAddress report whether value is a 0x-prefixed 20-byte hex address
+// common.IsHexAddress alone also accepts addresses without the prefix.
+func isHexAddress(value string) bool {
+	return strings.HasPrefix(value, "0x") && common.IsHexAddress(value)
+}
+
+// contains report whether value is in list
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
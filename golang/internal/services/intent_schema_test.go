@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+
+	"sample-game-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTokenAddress = "0x1234567890123456789012345678901234567890"
+
+// fields 오류 필드 목록 추출
+func fields(errs []models.FieldError) []string {
+	var result []string
+	for _, err := range errs {
+		result = append(result, err.Field)
+	}
+	return result
+}
+
+func TestValidateIntentSchema(t *testing.T) {
+	valid := []models.ExchangeIntent{
+		{
+			Type:   "assemble",
+			Method: "mint",
+			From:   []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1000}},
+			To:     []models.PairAsset{{Type: "erc20", AssetID: testTokenAddress, Amount: 1}},
+		},
+		{
+			Type:   "disassemble",
+			Method: "burn-permit",
+			From:   []models.PairAsset{{Type: "erc1155", AssetID: testTokenAddress, Amount: 1}},
+			To:     []models.PairAsset{{Type: "asset", AssetID: "item_gem", Amount: 10}},
+		},
+	}
+	for _, intent := range valid {
+		assert.Empty(t, ValidateIntent(intent), "%s/%s should be valid", intent.Type, intent.Method)
+	}
+
+	// 알 수 없는 타입
+	assert.Equal(t, []string{"intent.type"}, fields(ValidateIntent(models.ExchangeIntent{Type: "swap", Method: "mint"})))
+
+	// 타입과 메서드 조합 불일치 (assemble 에 burn)
+	errs := ValidateIntent(models.ExchangeIntent{
+		Type:   "assemble",
+		Method: "burn",
+		From:   []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1000}},
+		To:     []models.PairAsset{{Type: "erc20", AssetID: testTokenAddress, Amount: 1}},
+	})
+	assert.Equal(t, []string{"intent.method"}, fields(errs))
+
+	// 방향별 자산 타입, 0 수량, 범위를 넘는 수량, 중복 ID, 주소 형식
+	errs = ValidateIntent(models.ExchangeIntent{
+		Type:   "disassemble",
+		Method: "burn",
+		From: []models.PairAsset{
+			{Type: "asset", AssetID: "asset_money", Amount: 1},
+			{Type: "erc20", AssetID: "0x1234", Amount: 1},
+			{Type: "erc1155", AssetID: testTokenAddress[2:], Amount: 1},
+		},
+		To: []models.PairAsset{
+			{Type: "asset", AssetID: "item_gem", Amount: 0},
+			{Type: "asset", AssetID: "item_gem", Amount: 5},
+			{Type: "asset", AssetID: "item_ruby", Amount: 1<<63 + 5},
+		},
+	})
+	assert.Equal(t, []string{
+		"intent.from[0].type",
+		"intent.from[1].id",
+		"intent.from[2].id",
+		"intent.to[0].amount",
+		"intent.to[1].id",
+		"intent.to[2].amount",
+	}, fields(errs))
+
+	// 빈 from/to
+	assert.Equal(t, []string{"intent.from", "intent.to"}, fields(ValidateIntent(models.ExchangeIntent{Type: "assemble", Method: "mint"})))
+}
+
+func TestValidateRequestFields(t *testing.T) {
+	req := models.ValidateRequest{
+		UUID:        "uuid",
+		UserSig:     "not-hex",
+		UserAddress: "0xB777",
+		ProjectID:   "project",
+		Digest:      "0x1234",
+		Intent: models.ExchangeIntent{
+			Type:   "assemble",
+			Method: "mint",
+			From:   []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: 1000}},
+			To:     []models.PairAsset{{Type: "erc20", AssetID: testTokenAddress, Amount: 1}},
+		},
+	}
+	assert.Equal(t, []string{"user_address", "digest", "user_sig"}, fields(ValidateRequestFields(req)))
+
+	// 0x 접두사 없는 주소
+	req.UserAddress = "B777C937fa1afC99606aFa85c5b83cFe7f82BabD"
+	assert.Equal(t, []string{"user_address", "digest", "user_sig"}, fields(ValidateRequestFields(req)))
+
+	req.UserSig = "0xabcdef"
+	req.UserAddress = "0xB777C937fa1afC99606aFa85c5b83cFe7f82BabD"
+	req.Digest = "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+	assert.Empty(t, ValidateRequestFields(req))
+}
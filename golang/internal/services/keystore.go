@@ -1,9 +1,13 @@
 package services
 
 import (
+	"context"
 	"crypto/ecdsa"
 
+	"sample-game-backend/internal/tracing"
+
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -26,8 +30,15 @@ func NewKeystoreService() *KeystoreService {
 	}
 }
 
-func (s *KeystoreService) Sign(digest []byte) ([]byte, error) {
+// Address validator signer address
+func (s *KeystoreService) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *KeystoreService) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	_, span := tracing.Start(ctx, "KeystoreService.Sign")
 	signature, err := crypto.Sign(digest, s.key)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, err
 	}
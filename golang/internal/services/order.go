@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+)
+
+// RejectOrder mark an order that failed after it was stored at validate as rejected
+// Deducted assets are refunded and reserved credits and rule usage are released
+func RejectOrder(uuid, reason string) {
+	if _, err := closeOrder(uuid, reason); err != nil {
+		slog.Error("RejectOrder", "error", "Failed to reject order", "err", err, "uuid", uuid)
+	}
+}
+
+// closeOrder reject a validated order, refunding deducted assets and releasing its credit or rule usage
+func closeOrder(uuid, reason string) (*database.UUIDMapping, error) {
+	order, err := database.CloseOrder(uuid, database.OrderStatusRejected, reason, true, database.OrderStatusValidated)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation, err := database.GetReservation(uuid); err == nil && reservation != nil && reservation.Status == database.ReservationPending {
+		ReleaseCredit(uuid, database.ReservationReleased)
+		return order, nil
+	}
+	ReleaseRuleUsage(uuid)
+	return order, nil
+}
+
+// FlagRedeductFailed a successful result arrived after a refund and the assets could not be deducted again
+const FlagRedeductFailed = "rededuct_failed"
+
+// CompleteOrder record the result webhook on the order
+// A failed result refunds the assets deducted by an assemble validate. A successful result for an order
+// whose deduction was refunded on expiry deducts the assets again; when that fails the order is flagged
+// and left unsettled.
+func CompleteOrder(uuid string, receiptStatus uint64) error {
+	if receiptStatus != 1 {
+		return failOrder(uuid, fmt.Sprintf("result webhook with receipt status %d", receiptStatus))
+	}
+	status, reason := database.OrderStatusSettled, "result webhook"
+
+	current, err := database.GetOrder(uuid)
+	if err != nil {
+		return err
+	}
+	rededuct := status == database.OrderStatusSettled && current != nil && current.Refunded && current.Status == database.OrderStatusExpired
+	if rededuct {
+		slog.Warn("CompleteOrder", "audit", "late_result_after_refund", "uuid", uuid, "sessionID", current.SessionID, "deducted", current.Deducted)
+		if err := database.CheckAndDeductAssets(current.SessionID, current.Deducted, database.LedgerRef{Source: database.LedgerSourceResult, Reference: uuid, Reason: "late result after refund"}); err != nil {
+			slog.Error("CompleteOrder", "error", "Failed to deduct refunded assets again", "err", err, "uuid", uuid, "sessionID", current.SessionID)
+			if _, flagErr := database.FlagOrder(uuid, FlagRedeductFailed, err.Error()); flagErr != nil {
+				slog.Error("CompleteOrder", "error", "Failed to flag order", "err", flagErr, "uuid", uuid)
+			}
+			return fmt.Errorf("deduct refunded assets again: %w", err)
+		}
+	}
+
+	_, err = database.UpdateOrderStatus(uuid, status, reason, database.OrderStatusValidated, database.OrderStatusExpired)
+	if err != nil {
+		if rededuct {
+			// Another result completed the order first; return the assets deducted above
+			if refundErr := database.AddAssets(current.SessionID, current.Deducted, database.LedgerRef{Source: database.LedgerSourceRefund, Reference: uuid, Reason: "order completed concurrently"}); refundErr != nil {
+				slog.Error("CompleteOrder", "error", "Failed to return assets deducted again", "err", refundErr, "uuid", uuid, "sessionID", current.SessionID)
+			}
+		}
+		if errors.Is(err, database.ErrOrderNotFound) || errors.Is(err, database.ErrOrderStatusStale) {
+			// Orders stored without status or already completed
+			slog.Info("CompleteOrder", "uuid", uuid, "status", status, "action", "skipped", "reason", err.Error())
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// failOrder mark an order whose transaction failed on-chain, refunding its deducted assets
+// Refunded assets no longer count as consumed, so the order's rule usage is released.
+func failOrder(uuid, reason string) error {
+	order, err := database.CloseOrder(uuid, database.OrderStatusFailed, reason, true, database.OrderStatusValidated, database.OrderStatusExpired)
+	if err != nil {
+		if errors.Is(err, database.ErrOrderNotFound) || errors.Is(err, database.ErrOrderStatusStale) {
+			// Orders stored without status or already completed
+			slog.Info("CompleteOrder", "uuid", uuid, "status", database.OrderStatusFailed, "action", "skipped", "reason", err.Error())
+			return nil
+		}
+		return err
+	}
+	if order.Refunded && order.IntentType == models.IntentTypeAssemble {
+		ReleaseRuleUsage(uuid)
+	}
+	return nil
+}
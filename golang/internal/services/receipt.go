@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/receipt"
+	"sample-game-backend/internal/tracing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// receiptVerification check result receipts against the stored order (disabled by default)
+	receiptVerification bool
+	// receiptConfirmer confirm posted receipts on-chain (nil when no RPC endpoint is configured)
+	receiptConfirmer *receipt.Confirmer
+	confirmTimeout   time.Duration
+	confirmMinAmount uint64
+	receiptMu        sync.RWMutex
+)
+
+// InitReceiptVerification configure result receipt verification and on-chain confirmation
+func InitReceiptVerification(cfg config.ReceiptConfig) error {
+	var confirmer *receipt.Confirmer
+	if cfg.RPCURL != "" {
+		client, err := ethclient.Dial(cfg.RPCURL)
+		if err != nil {
+			return fmt.Errorf("dial receipt rpc endpoint: %w", err)
+		}
+		confirmer = receipt.NewConfirmer(client, cfg.Confirmations)
+		slog.Info("InitReceiptVerification", "rpcURL", cfg.RPCURL, "confirmations", cfg.Confirmations, "minAmount", cfg.MinAmount)
+	}
+
+	SetReceiptConfirmer(confirmer, cfg.ConfirmTimeout, cfg.MinAmount)
+	receiptMu.Lock()
+	defer receiptMu.Unlock()
+	receiptVerification = cfg.Verify
+	return nil
+}
+
+// SetReceiptConfirmer replace the on-chain confirmer (nil disables confirmation)
+func SetReceiptConfirmer(confirmer *receipt.Confirmer, timeout time.Duration, minAmount uint64) {
+	receiptMu.Lock()
+	defer receiptMu.Unlock()
+	receiptConfirmer = confirmer
+	confirmTimeout = timeout
+	confirmMinAmount = minAmount
+}
+
+// VerifyResultReceipt check the result's receipt against its tx_hash and the stored order's intent,
+// then against the on-chain receipt when a confirmer is configured
+// Returns *receipt.Mismatch when the receipt disagrees. Orders stored without an intent only get the tx hash check.
+func VerifyResultReceipt(ctx context.Context, req models.ExchangeReq) (err error) {
+	ctx, span := tracing.Start(ctx, "VerifyResultReceipt", attribute.String("uuid", req.UUID), attribute.String("tx_hash", req.TxHash.Hex()))
+	defer func() { tracing.End(span, err) }()
+
+	receiptMu.RLock()
+	enabled, confirmer, timeout, minAmount := receiptVerification, receiptConfirmer, confirmTimeout, confirmMinAmount
+	receiptMu.RUnlock()
+	if !enabled && confirmer == nil {
+		return nil
+	}
+
+	order, err := database.GetOrder(req.UUID)
+	if err != nil {
+		return err
+	}
+	var tokens []models.PairAsset
+	var flow *receipt.Flow
+	if order != nil && order.Intent != nil {
+		tokens = tokenSide(*order.Intent)
+		flow = tokenFlow(order)
+	}
+
+	if enabled {
+		if tokens == nil {
+			slog.Warn("VerifyResultReceipt", "warning", "No stored intent, transfer logs not checked", "uuid", req.UUID)
+		}
+		if err := receipt.Verify(&req.Receipt, req.TxHash, tokens, flow); err != nil {
+			return err
+		}
+	}
+
+	// Orders without a stored intent have an unknown size and are always confirmed
+	if confirmer == nil || (tokens != nil && tokenAmount(tokens) < minAmount) {
+		return nil
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	onChain, err := confirmer.Confirm(ctx, &req.Receipt, req.TxHash)
+	if err != nil {
+		return err
+	}
+	slog.Info("VerifyResultReceipt", "uuid", req.UUID, "txHash", req.TxHash.Hex(), "blockNumber", onChain.BlockNumber, "action", "confirmed on-chain")
+	return nil
+}
+
+// tokenSide on-chain tokens of the intent (minted by assemble, burned by disassemble)
+func tokenSide(intent models.ExchangeIntent) []models.PairAsset {
+	side := intent.To
+	if intent.Type == models.IntentTypeDisassemble {
+		side = intent.From
+	}
+
+	tokens := []models.PairAsset{}
+	for _, pair := range side {
+		if !strings.EqualFold(pair.Type, PairAssetTypeAsset) {
+			tokens = append(tokens, pair)
+		}
+	}
+	return tokens
+}
+
+// tokenFlow direction of the order's tokens: minted to the user by assemble, burned from the user by disassemble
+// Orders stored without user_address are checked in any direction.
+func tokenFlow(order *database.UUIDMapping) *receipt.Flow {
+	if !common.IsHexAddress(order.UserAddress) {
+		return nil
+	}
+	user := common.HexToAddress(order.UserAddress)
+	if order.Intent.Type == models.IntentTypeDisassemble {
+		return receipt.Burn(user)
+	}
+	return receipt.Mint(user)
+}
+
+// tokenAmount total raw amount of the tokens
+func tokenAmount(tokens []models.PairAsset) uint64 {
+	var total uint64
+	for _, token := range tokens {
+		total += uint64(token.Amount)
+	}
+	return total
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/orderquery"
+)
+
+// OrderQuerier queries the authoritative order status (implemented by orderquery.Client)
+type OrderQuerier interface {
+	GetOrder(ctx context.Context, network, uuid string) (*orderquery.Order, error)
+}
+
+// Reconciler settles orders whose result webhook was missed using the Order Information Query API
+type Reconciler struct {
+	querier  OrderQuerier
+	network  string
+	interval time.Duration
+	minAge   time.Duration
+	lookback time.Duration
+	// now clock used to select orders (replaceable in tests)
+	now func() time.Time
+}
+
+// NewReconciler create reconciler from configuration
+func NewReconciler(querier OrderQuerier, cfg config.ReconcileConfig) *Reconciler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	return &Reconciler{
+		querier:  querier,
+		network:  cfg.Network,
+		interval: cfg.Interval,
+		minAge:   cfg.MinAge,
+		lookback: cfg.Lookback,
+		now:      time.Now,
+	}
+}
+
+// WithClock replace the reconciler's clock
+func (r *Reconciler) WithClock(now func() time.Time) *Reconciler {
+	r.now = now
+	return r
+}
+
+// Run reconcile periodically until the context is canceled
+func (r *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reconcile(ctx); err != nil {
+				slog.Error("Reconciler", "error", "Reconciliation failed", "err", err)
+			}
+		}
+	}
+}
+
+// Reconcile query pending and expired orders once, returning the number of orders settled
+func (r *Reconciler) Reconcile(ctx context.Context) (int, error) {
+	now := r.now()
+
+	// Validated orders still waiting for their result webhook
+	validated, err := database.ListOrdersByStatus(database.OrderStatusValidated)
+	if err != nil {
+		return 0, err
+	}
+	// Expired orders may have completed on-chain after the deadline
+	expired, err := database.ListOrdersByStatus(database.OrderStatusExpired)
+	if err != nil {
+		return 0, err
+	}
+
+	var candidates []*database.UUIDMapping
+	for _, order := range validated {
+		if now.Sub(order.CreatedAt) >= r.minAge {
+			candidates = append(candidates, order)
+		}
+	}
+	for _, order := range expired {
+		if now.Sub(order.CreatedAt) <= r.lookback {
+			candidates = append(candidates, order)
+		}
+	}
+
+	settled := 0
+	for _, order := range candidates {
+		if err := ctx.Err(); err != nil {
+			return settled, err
+		}
+		applied, err := r.reconcileOrder(ctx, order)
+		if err != nil {
+			slog.Error("Reconciler", "error", "Failed to reconcile order", "err", err, "uuid", order.UUID)
+			continue
+		}
+		if applied {
+			settled++
+		}
+	}
+
+	return settled, nil
+}
+
+// reconcileOrder apply the remote result of an order (false while the order is still in progress)
+func (r *Reconciler) reconcileOrder(ctx context.Context, local *database.UUIDMapping) (bool, error) {
+	remote, err := r.querier.GetOrder(ctx, r.network, local.UUID)
+	if err != nil {
+		if errors.Is(err, orderquery.ErrOrderNotFound) {
+			slog.Info("Reconciler", "uuid", local.UUID, "action", "skipped", "reason", "order not found")
+			return false, nil
+		}
+		return false, err
+	}
+
+	if remote.SessionID != "" && remote.SessionID != local.SessionID {
+		slog.Error("Reconciler", "error", "Order session mismatch", "uuid", local.UUID, "localSessionID", local.SessionID, "remoteSessionID", remote.SessionID)
+		return false, nil
+	}
+	if intentType := remote.IntentType(); intentType != "" && local.IntentType != "" && intentType != local.IntentType {
+		slog.Error("Reconciler", "error", "Order type mismatch", "uuid", local.UUID, "localIntentType", local.IntentType, "remoteOrderType", remote.OrderType)
+		return false, nil
+	}
+
+	var receiptStatus uint64
+	switch {
+	case remote.Succeeded():
+		receiptStatus = 1
+	case remote.Failed():
+		receiptStatus = 0
+	default:
+		slog.Info("Reconciler", "uuid", local.UUID, "orderStatus", remote.OrderStatus, "action", "skipped", "reason", "order in progress")
+		return false, nil
+	}
+
+	intentType := local.IntentType
+	if intentType == "" {
+		intentType = remote.IntentType()
+	}
+	intent := models.ExchangeIntent{
+		Type: intentType,
+		From: remote.From,
+		To:   remote.To,
+	}
+	if err := SettleOrder(local.UUID, remote.TxHash, intent, receiptStatus); err != nil {
+		return false, err
+	}
+
+	slog.Warn("Reconciler", "audit", "order_reconciled", "uuid", local.UUID, "sessionID", local.SessionID, "previousStatus", local.Status, "orderStatus", remote.OrderStatus, "txHash", remote.TxHash)
+	return true, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/orderquery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubOrderAPI 주문 조회 API 스텁 서버
+func stubOrderAPI(t *testing.T, orders map[string]orderquery.Order) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order, ok := orders[r.URL.Query().Get("uuid")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "not found"}`))
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"code": 200, "message": "OK", "data": order}))
+	}))
+}
+
+func TestReconcile(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "reconcile-session"
+	before := moneyBalance(t, sessionID)
+
+	// 결과 웹훅을 받지 못한 분해 주문
+	intent := disassembleMoney(700)
+	require.NoError(t, database.StoreOrder(&database.UUIDMapping{UUID: "reconcile-success", SessionID: sessionID, ProjectID: "project", IntentType: models.IntentTypeDisassemble}))
+	require.NoError(t, ReserveCredit("project", sessionID, "reconcile-success", intent))
+
+	// 온체인에서 실패한 조합 주문
+	validateAssemble(t, "reconcile-failed", sessionID, 100)
+
+	// 아직 진행 중인 주문과 CROSS RAMP에 없는 주문
+	validateAssemble(t, "reconcile-pending", sessionID, 10)
+	validateAssemble(t, "reconcile-missing", sessionID, 10)
+
+	server := stubOrderAPI(t, map[string]orderquery.Order{
+		"reconcile-success": {UUID: "reconcile-success", SessionID: sessionID, OrderType: orderquery.OrderTypeTokenToItem, OrderStatus: "success", From: intent.From, To: intent.To},
+		"reconcile-failed":  {UUID: "reconcile-failed", SessionID: sessionID, OrderType: orderquery.OrderTypeItemToToken, OrderStatus: "fail"},
+		"reconcile-pending": {UUID: "reconcile-pending", SessionID: sessionID, OrderType: orderquery.OrderTypeItemToToken, OrderStatus: "pending"},
+	})
+	defer server.Close()
+
+	now := time.Now()
+	reconciler := NewReconciler(orderquery.NewClient(server.URL, server.Client()), config.ReconcileConfig{Network: "cross-testnet", MinAge: 5 * time.Minute, Lookback: 24 * time.Hour}).
+		WithClock(func() time.Time { return now })
+
+	// 최소 대기 시간 전에는 조회하지 않음
+	settled, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, settled)
+
+	now = now.Add(10 * time.Minute)
+	settled, err = reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, settled)
+
+	// 성공한 분해 주문은 지급, 실패한 조합 주문은 환불, 진행 중 주문은 잔액 변화 없음
+	assert.Equal(t, before+700-20, moneyBalance(t, sessionID))
+
+	expected := map[string]string{
+		"reconcile-success": database.OrderStatusSettled,
+		"reconcile-failed":  database.OrderStatusFailed,
+		"reconcile-pending": database.OrderStatusValidated,
+		"reconcile-missing": database.OrderStatusValidated,
+	}
+	for uuid, status := range expected {
+		order, err := database.GetOrder(uuid)
+		require.NoError(t, err)
+		assert.Equal(t, status, order.Status, uuid)
+	}
+
+	// 이미 정산된 주문은 다시 처리하지 않음
+	settled, err = reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, settled)
+	assert.Equal(t, before+700-20, moneyBalance(t, sessionID))
+}
+
+func TestReconcileLookback(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "reconcile-lookback"
+	validateAssemble(t, "reconcile-lookback", sessionID, 10)
+	_, err := database.UpdateOrderStatus("reconcile-lookback", database.OrderStatusExpired, "test", database.OrderStatusValidated)
+	require.NoError(t, err)
+
+	server := stubOrderAPI(t, map[string]orderquery.Order{
+		"reconcile-lookback": {UUID: "reconcile-lookback", SessionID: sessionID, OrderType: orderquery.OrderTypeItemToToken, OrderStatus: "fail"},
+	})
+	defer server.Close()
+
+	// 조회 기간이 지난 만료 주문은 다시 조회하지 않음
+	now := time.Now().Add(2 * time.Hour)
+	reconciler := NewReconciler(orderquery.NewClient(server.URL, server.Client()), config.ReconcileConfig{Network: "cross-testnet", Lookback: time.Hour}).
+		WithClock(func() time.Time { return now })
+	settled, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, settled)
+
+	now = time.Now()
+	settled, err = reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, settled)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+)
+
+var (
+	// reservationTTL time a pending credit waits for the result webhook
+	reservationTTL = time.Hour
+	reservationMu  sync.RWMutex
+)
+
+// InitReservations configure pending credits of disassemble orders
+func InitReservations(cfg config.ReservationConfig) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Hour
+	}
+
+	reservationMu.Lock()
+	defer reservationMu.Unlock()
+	reservationTTL = cfg.TTL
+}
+
+// ReserveCredit record the in-game assets a disassemble order will credit
+// The credit stays pending until the result webhook confirms or releases it
+func ReserveCredit(projectID, sessionID, uuid string, intent models.ExchangeIntent) error {
+	reservationMu.RLock()
+	ttl := reservationTTL
+	reservationMu.RUnlock()
+
+	now := time.Now()
+	return database.CreateReservation(&database.Reservation{
+		UUID:      uuid,
+		ProjectID: projectID,
+		SessionID: sessionID,
+		Assets:    append([]models.PairAsset(nil), intent.To...),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+}
+
+// ReleaseCredit drop pending credit of an order that failed
+// The order's rule usage is released as well so it no longer counts toward limits
+func ReleaseCredit(uuid, status string) {
+	if _, err := database.ReleaseReservation(uuid, status); err != nil {
+		slog.Error("ReleaseCredit", "error", "Failed to release reservation", "err", err, "uuid", uuid, "status", status)
+		return
+	}
+	ReleaseRuleUsage(uuid)
+}
+
+// ExpireReservations release pending credits of the session that outlived the TTL
+func ExpireReservations(sessionID string, now time.Time) error {
+	reservations, err := database.ListPendingReservations(sessionID)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		if now.Before(reservation.ExpiresAt) {
+			continue
+		}
+		slog.Warn("ExpireReservations", "warning", "No result received before expiry", "uuid", reservation.UUID, "sessionID", sessionID, "expiresAt", reservation.ExpiresAt)
+		ReleaseCredit(reservation.UUID, database.ReservationExpired)
+	}
+	return nil
+}
+
+// PendingCredits total pending credit of the session by asset ID
+// Expired reservations are released first
+func PendingCredits(sessionID string) (map[string]uint64, error) {
+	if err := ExpireReservations(sessionID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	reservations, err := database.ListPendingReservations(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]uint64)
+	for _, reservation := range reservations {
+		for _, asset := range reservation.Assets {
+			pending[asset.AssetID] += uint64(asset.Amount)
+		}
+	}
+	return pending, nil
+}
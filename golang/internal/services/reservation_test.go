@@ -0,0 +1,126 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moneyBalance 세션의 asset_money 잔액 조회
+func moneyBalance(t *testing.T, sessionID string) uint64 {
+	sessionAssets, err := database.GetOrCreateSessionAssets(sessionID)
+	require.NoError(t, err)
+	balance, err := strconv.ParseUint(sessionAssets.Assets["asset_money"], 10, 64)
+	require.NoError(t, err)
+	return balance
+}
+
+func disassembleMoney(amount uint) models.ExchangeIntent {
+	return models.ExchangeIntent{
+		Type:   models.IntentTypeDisassemble,
+		Method: "burn",
+		From:   []models.PairAsset{{Type: "erc20", AssetID: "0x1234567890123456789012345678901234567890", Amount: 1}},
+		To:     []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: amount}},
+	}
+}
+
+func TestReservationConfirm(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "reservation-confirm"
+	intent := disassembleMoney(1000)
+	before := moneyBalance(t, sessionID)
+
+	require.NoError(t, ReserveRuleUsage("project", sessionID, "order-confirm", intent))
+	require.NoError(t, ReserveCredit("project", sessionID, "order-confirm", intent))
+	assert.ErrorIs(t, ReserveCredit("project", sessionID, "order-confirm", intent), database.ErrReservationExists)
+
+	// 결과 전에는 pending으로만 표시되고 잔액은 그대로
+	pending, err := PendingCredits(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), pending["asset_money"])
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+
+	// 성공 결과로 확정, 재전송된 웹훅은 중복 지급하지 않음
+	require.NoError(t, ProcessExchangeResult("order-confirm", sessionID, intent.To, 1))
+	require.NoError(t, ProcessExchangeResult("order-confirm", sessionID, intent.To, 1))
+	assert.Equal(t, before+1000, moneyBalance(t, sessionID))
+
+	pending, err = PendingCredits(sessionID)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// 확정된 주문의 발행량은 한도 계산에 남아 있음
+	usages, err := database.ListCharacterUsage("project", sessionID, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, usages, 1)
+}
+
+func TestReservationReleaseOnFailure(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "reservation-failure"
+	intent := disassembleMoney(500)
+	before := moneyBalance(t, sessionID)
+
+	require.NoError(t, ReserveRuleUsage("project", sessionID, "order-failure", intent))
+	require.NoError(t, ReserveCredit("project", sessionID, "order-failure", intent))
+
+	// 실패 결과는 예약과 사용량을 해제
+	require.NoError(t, ProcessExchangeResult("order-failure", sessionID, intent.To, 0))
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+
+	reservation, err := database.GetReservation("order-failure")
+	require.NoError(t, err)
+	assert.Equal(t, database.ReservationReleased, reservation.Status)
+
+	usages, err := database.ListCharacterUsage("project", sessionID, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
+
+func TestReservationExpiry(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "reservation-expiry"
+	intent := disassembleMoney(300)
+	before := moneyBalance(t, sessionID)
+
+	require.NoError(t, ReserveRuleUsage("project", sessionID, "order-expiry", intent))
+	require.NoError(t, ReserveCredit("project", sessionID, "order-expiry", intent))
+
+	// TTL 이전에는 유지
+	require.NoError(t, ExpireReservations(sessionID, time.Now()))
+	pending, err := PendingCredits(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300), pending["asset_money"])
+
+	// TTL 이후 만료되어 pending과 사용량에서 제외
+	require.NoError(t, ExpireReservations(sessionID, time.Now().Add(2*time.Hour)))
+	pending, err = PendingCredits(sessionID)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	usages, err := database.ListCharacterUsage("project", sessionID, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+
+	// 만료 후 도착한 성공 결과도 지급 (토큰은 이미 소각됨)
+	require.NoError(t, ProcessExchangeResult("order-expiry", sessionID, intent.To, 1))
+	assert.Equal(t, before+300, moneyBalance(t, sessionID))
+
+	// 늦게 확정된 발행량도 한도 계산에 다시 포함
+	usages, err = database.ListCharacterUsage("project", sessionID, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, uint64(300), usages[0].Issued["asset_money"])
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/rules"
+)
+
+var (
+	// rulesEngine business rules evaluated in the validate path (no rules when nil)
+	rulesEngine *rules.Engine
+	// rulesMu serializes evaluation and usage recording so concurrent orders cannot both pass a limit
+	rulesMu sync.Mutex
+)
+
+// databaseUsageSource rules.UsageSource backed by the database
+type databaseUsageSource struct{}
+
+func (databaseUsageSource) CharacterUsage(projectID, sessionID string, since time.Time) ([]models.OrderUsage, error) {
+	return database.ListCharacterUsage(projectID, sessionID, since)
+}
+
+func (databaseUsageSource) ProjectUsage(projectID string, since time.Time) ([]models.OrderUsage, error) {
+	return database.ListProjectUsage(projectID, since)
+}
+
+// InitRules load business rules from file
+func InitRules(path string) error {
+	engine, err := rules.Load(path)
+	if err != nil {
+		slog.Error("InitRules", "error", "Failed to load rules", "err", err, "path", path)
+		return err
+	}
+	SetRulesEngine(engine)
+	slog.Info("InitRules", "status", "success", "path", path)
+	return nil
+}
+
+// SetRulesEngine replace business rules engine (nil disables rules)
+func SetRulesEngine(engine *rules.Engine) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rulesEngine = engine
+}
+
+// RuleUsageLookback how far back order usage can still affect the configured rules (0 without rules)
+func RuleUsageLookback() time.Duration {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	if rulesEngine == nil {
+		return 0
+	}
+	return rulesEngine.UsageLookback()
+}
+
+// ReserveRuleUsage evaluate business rules and record the order's usage
+// Returns *rules.Violation when a rule rejects the order. The usage counts toward
+// later orders' limits until released with ReleaseRuleUsage.
+func ReserveRuleUsage(projectID, sessionID, uuid string, intent models.ExchangeIntent) error {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
+	now := time.Now()
+	if rulesEngine != nil {
+		err := rulesEngine.Evaluate(rules.Request{
+			ProjectID: projectID,
+			SessionID: sessionID,
+			Intent:    intent,
+			Now:       now,
+		}, databaseUsageSource{})
+		if err != nil {
+			return err
+		}
+	}
+
+	consumed, issued := rules.UsageFromIntent(intent)
+	return database.StoreOrderUsage(&models.OrderUsage{
+		UUID:       uuid,
+		ProjectID:  projectID,
+		SessionID:  sessionID,
+		IntentType: intent.Type,
+		Consumed:   consumed,
+		Issued:     issued,
+		CreatedAt:  now,
+	})
+}
+
+// ReleaseRuleUsage release usage of an order that did not go through
+func ReleaseRuleUsage(uuid string) {
+	if err := database.DeleteOrderUsage(uuid); err != nil {
+		slog.Error("ReleaseRuleUsage", "error", "Failed to release rule usage", "err", err, "uuid", uuid)
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+)
+
+// expiryReason history reason recorded on orders expired by the sweeper
+const expiryReason = "no result webhook before deadline"
+
+// OrderSweeper expires validated orders that never receive a result webhook
+type OrderSweeper struct {
+	deadline  time.Duration
+	interval  time.Duration
+	refund    bool
+	retention time.Duration
+	// now clock used to compare deadlines (replaceable in tests)
+	now func() time.Time
+}
+
+// NewOrderSweeper create sweeper from configuration
+func NewOrderSweeper(cfg config.OrderExpiryConfig) *OrderSweeper {
+	if cfg.Deadline <= 0 {
+		cfg.Deadline = time.Hour
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	return &OrderSweeper{
+		deadline:  cfg.Deadline,
+		interval:  cfg.Interval,
+		refund:    cfg.Refund,
+		retention: cfg.Retention,
+		now:       time.Now,
+	}
+}
+
+// WithClock replace the sweeper's clock
+func (s *OrderSweeper) WithClock(now func() time.Time) *OrderSweeper {
+	s.now = now
+	return s
+}
+
+// Run sweep periodically until the context is canceled
+func (s *OrderSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Sweep(); err != nil {
+				slog.Error("OrderSweeper", "error", "Sweep failed", "err", err)
+			}
+		}
+	}
+}
+
+// Sweep expire stale orders and reservations once, returning the number of expired orders
+func (s *OrderSweeper) Sweep() (int, error) {
+	now := s.now()
+
+	orders, err := database.ListOrdersByStatus(database.OrderStatusValidated)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, order := range orders {
+		if now.Sub(order.CreatedAt) < s.deadline {
+			continue
+		}
+		if err := s.expireOrder(order); err != nil {
+			if errors.Is(err, database.ErrOrderStatusStale) {
+				continue // result arrived while sweeping
+			}
+			slog.Error("OrderSweeper", "error", "Failed to expire order", "err", err, "uuid", order.UUID)
+			continue
+		}
+		expired++
+	}
+
+	// Reservations have their own TTL and may expire before the order deadline
+	reservations, err := database.ListReservationsByStatus(database.ReservationPending)
+	if err != nil {
+		return expired, err
+	}
+	for _, reservation := range reservations {
+		if now.Before(reservation.ExpiresAt) {
+			continue
+		}
+		slog.Warn("OrderSweeper", "audit", "reservation_expired", "uuid", reservation.UUID, "sessionID", reservation.SessionID, "assets", reservation.Assets)
+		ReleaseCredit(reservation.UUID, database.ReservationExpired)
+	}
+
+	// Sessions are evicted once their orders have settled or expired
+	if _, err := database.EvictIdleSessions(now); err != nil {
+		return expired, err
+	}
+
+	if s.retention > 0 {
+		if err := s.purgeFinishedOrders(now); err != nil {
+			return expired, err
+		}
+	}
+
+	return expired, nil
+}
+
+// expireOrder move order to expired, refunding deductions and releasing credits and usage
+func (s *OrderSweeper) expireOrder(order *database.UUIDMapping) error {
+	updated, err := database.CloseOrder(order.UUID, database.OrderStatusExpired, expiryReason, s.refund, database.OrderStatusValidated)
+	if err != nil {
+		return err
+	}
+
+	switch updated.IntentType {
+	case models.IntentTypeAssemble:
+		// Refunded assets no longer count as consumed
+		if updated.Refunded {
+			ReleaseRuleUsage(updated.UUID)
+		}
+	case models.IntentTypeDisassemble:
+		if reservation, err := database.GetReservation(updated.UUID); err == nil && reservation != nil && reservation.Status == database.ReservationPending {
+			ReleaseCredit(updated.UUID, database.ReservationExpired)
+		}
+	}
+
+	slog.Warn("OrderSweeper", "audit", "order_expired",
+		"uuid", updated.UUID,
+		"sessionID", updated.SessionID,
+		"projectID", updated.ProjectID,
+		"intentType", updated.IntentType,
+		"createdAt", updated.CreatedAt,
+		"deducted", updated.Deducted,
+		"refunded", updated.Refunded)
+	return nil
+}
+
+// purgeFinishedOrders remove finished orders older than the retention period
+func (s *OrderSweeper) purgeFinishedOrders(now time.Time) error {
+	// Orders are kept through the result retry window so late results still settle
+	retention := max(s.retention, config.ResultRetryWindow)
+	for _, status := range []string{database.OrderStatusSettled, database.OrderStatusFailed, database.OrderStatusExpired, database.OrderStatusRejected} {
+		orders, err := database.ListOrdersByStatus(status)
+		if err != nil {
+			return err
+		}
+		for _, order := range orders {
+			if now.Sub(order.UpdatedAt) < retention {
+				continue
+			}
+			if err := database.DeleteOrder(order.UUID); err != nil {
+				return err
+			}
+			slog.Info("OrderSweeper", "audit", "order_purged", "uuid", order.UUID, "status", order.Status)
+		}
+	}
+
+	// Usage of purged orders is dropped once no rule period or cooldown can still read it
+	purged, err := database.PurgeOrderUsage(now.Add(-max(retention, RuleUsageLookback())))
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		slog.Info("OrderSweeper", "action", "order usage purged", "count", purged)
+	}
+	return nil
+}
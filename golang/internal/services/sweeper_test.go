@@ -0,0 +1,269 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"sample-game-backend/internal/config"
+	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/rules"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validateAssemble validate 경로처럼 차감 후 주문 저장
+func validateAssemble(t *testing.T, uuid, sessionID string, amount uint) {
+	from := []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: amount}}
+	require.NoError(t, database.CheckAndDeductAssets(sessionID, from, database.LedgerRef{}))
+	require.NoError(t, database.StoreOrder(&database.UUIDMapping{
+		UUID:       uuid,
+		SessionID:  sessionID,
+		ProjectID:  "project",
+		IntentType: models.IntentTypeAssemble,
+		Deducted:   from,
+	}))
+}
+
+func TestOrderSweeperRefund(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "sweeper-refund"
+	before := moneyBalance(t, sessionID)
+	validateAssemble(t, "sweep-assemble", sessionID, 100)
+	assert.Equal(t, before-100, moneyBalance(t, sessionID))
+
+	now := time.Now()
+	sweeper := NewOrderSweeper(config.OrderExpiryConfig{Deadline: time.Hour, Refund: true}).
+		WithClock(func() time.Time { return now })
+
+	// 기한 전에는 유지
+	_, err := sweeper.Sweep()
+	require.NoError(t, err)
+	order, err := database.GetOrder("sweep-assemble")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusValidated, order.Status)
+
+	// 기한 이후 만료 및 환불
+	now = now.Add(2 * time.Hour)
+	expired, err := sweeper.Sweep()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, expired, 1)
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+
+	order, err = database.GetOrder("sweep-assemble")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusExpired, order.Status)
+	assert.True(t, order.Refunded)
+	require.Len(t, order.History, 2)
+	assert.Equal(t, expiryReason, order.History[1].Reason)
+
+	// 환불 후 도착한 성공 결과는 다시 차감
+	require.NoError(t, CompleteOrder("sweep-assemble", 1))
+	assert.Equal(t, before-100, moneyBalance(t, sessionID))
+
+	order, err = database.GetOrder("sweep-assemble")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusSettled, order.Status)
+}
+
+func TestOrderSweeperWithoutRefund(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "sweeper-no-refund"
+	before := moneyBalance(t, sessionID)
+	validateAssemble(t, "sweep-no-refund", sessionID, 50)
+
+	// 결과를 받은 주문은 만료되지 않음
+	validateAssemble(t, "sweep-settled", sessionID, 10)
+	require.NoError(t, CompleteOrder("sweep-settled", 1))
+
+	now := time.Now().Add(2 * time.Hour)
+	sweeper := NewOrderSweeper(config.OrderExpiryConfig{Deadline: time.Hour}).
+		WithClock(func() time.Time { return now })
+	_, err := sweeper.Sweep()
+	require.NoError(t, err)
+	assert.Equal(t, before-60, moneyBalance(t, sessionID))
+
+	order, err := database.GetOrder("sweep-no-refund")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusExpired, order.Status)
+	assert.False(t, order.Refunded)
+
+	order, err = database.GetOrder("sweep-settled")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusSettled, order.Status)
+}
+
+func TestOrderSweeperDisassembleAndRetention(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "sweeper-disassemble"
+	intent := disassembleMoney(200)
+	require.NoError(t, ReserveRuleUsage("project", sessionID, "sweep-disassemble", intent))
+	require.NoError(t, database.StoreOrder(&database.UUIDMapping{
+		UUID:       "sweep-disassemble",
+		SessionID:  sessionID,
+		ProjectID:  "project",
+		IntentType: models.IntentTypeDisassemble,
+	}))
+	require.NoError(t, ReserveCredit("project", sessionID, "sweep-disassemble", intent))
+
+	now := time.Now().Add(2 * time.Hour)
+	sweeper := NewOrderSweeper(config.OrderExpiryConfig{Deadline: time.Hour, Retention: 24 * time.Hour}).
+		WithClock(func() time.Time { return now })
+	_, err := sweeper.Sweep()
+	require.NoError(t, err)
+
+	reservation, err := database.GetReservation("sweep-disassemble")
+	require.NoError(t, err)
+	assert.Equal(t, database.ReservationExpired, reservation.Status)
+
+	// 보존 기간이 지나면 종료된 주문을 예약, 사용량과 함께 삭제
+	now = now.Add(48 * time.Hour)
+	_, err = sweeper.Sweep()
+	require.NoError(t, err)
+	order, err := database.GetOrder("sweep-disassemble")
+	require.NoError(t, err)
+	assert.Nil(t, order)
+	reservation, err = database.GetReservation("sweep-disassemble")
+	require.NoError(t, err)
+	assert.Nil(t, reservation)
+	usages, err := database.ListCharacterUsage("project", sessionID, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
+
+func TestOrderSweeperKeepsOrdersThroughRetryWindow(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	require.NoError(t, database.StoreOrder(&database.UUIDMapping{UUID: "sweep-retry-window", SessionID: "sweeper-retry", Status: database.OrderStatusExpired}))
+
+	// 보존 기간이 짧아도 결과 재시도 기간 동안은 삭제하지 않음
+	now := time.Now().Add(2 * time.Hour)
+	sweeper := NewOrderSweeper(config.OrderExpiryConfig{Deadline: time.Hour, Retention: time.Hour}).
+		WithClock(func() time.Time { return now })
+	_, err := sweeper.Sweep()
+	require.NoError(t, err)
+	order, err := database.GetOrder("sweep-retry-window")
+	require.NoError(t, err)
+	assert.NotNil(t, order)
+
+	now = now.Add(config.ResultRetryWindow)
+	_, err = sweeper.Sweep()
+	require.NoError(t, err)
+	order, err = database.GetOrder("sweep-retry-window")
+	require.NoError(t, err)
+	assert.Nil(t, order)
+}
+
+func TestCompleteOrderRedeductFailure(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "sweeper-rededuct-failure"
+	before := moneyBalance(t, sessionID)
+	validateAssemble(t, "sweep-rededuct-failure", sessionID, 100)
+
+	now := time.Now().Add(2 * time.Hour)
+	sweeper := NewOrderSweeper(config.OrderExpiryConfig{Deadline: time.Hour, Refund: true}).
+		WithClock(func() time.Time { return now })
+	_, err := sweeper.Sweep()
+	require.NoError(t, err)
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+
+	// 환불된 자산을 이미 사용한 경우
+	spend := []models.PairAsset{{Type: "asset", AssetID: "asset_money", Amount: uint(before)}}
+	require.NoError(t, database.CheckAndDeductAssets(sessionID, spend, database.LedgerRef{}))
+
+	// 재차감 실패 시 정산하지 않고 주문에 표시
+	require.Error(t, CompleteOrder("sweep-rededuct-failure", 1))
+	order, err := database.GetOrder("sweep-rededuct-failure")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusExpired, order.Status)
+	assert.Contains(t, order.Flags, FlagRedeductFailed)
+}
+
+func TestCompleteOrderFailedRefund(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	sessionID := "complete-failed-refund"
+	before := moneyBalance(t, sessionID)
+	validateAssemble(t, "complete-failed-refund", sessionID, 100)
+	require.Equal(t, before-100, moneyBalance(t, sessionID))
+
+	// 온체인 실패 결과를 받으면 차감된 자산을 환불
+	require.NoError(t, CompleteOrder("complete-failed-refund", 0))
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+	order, err := database.GetOrder("complete-failed-refund")
+	require.NoError(t, err)
+	assert.Equal(t, database.OrderStatusFailed, order.Status)
+	assert.True(t, order.Refunded)
+
+	// 중복 웹훅으로 다시 환불하지 않음
+	require.NoError(t, CompleteOrder("complete-failed-refund", 0))
+	assert.Equal(t, before, moneyBalance(t, sessionID))
+}
+
+func TestOrderSweeperKeepsRuleUsage(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	engine, err := rules.Parse([]byte(`{"projects": {"sweep-rules": [{"type": "order_count", "period": "weekly", "max": 1}]}}`))
+	require.NoError(t, err)
+	SetRulesEngine(engine)
+	defer SetRulesEngine(nil)
+
+	sessionID := "sweeper-rule-usage"
+	require.NoError(t, ReserveRuleUsage("sweep-rules", sessionID, "sweep-rule-usage", disassembleMoney(10)))
+	require.NoError(t, database.StoreOrder(&database.UUIDMapping{UUID: "sweep-rule-usage", SessionID: sessionID, ProjectID: "sweep-rules", Status: database.OrderStatusSettled}))
+
+	// 보존 기간이 지나 주문을 삭제해도 주간 규칙이 읽는 사용량은 유지
+	now := time.Now().Add(2 * config.ResultRetryWindow)
+	sweeper := NewOrderSweeper(config.OrderExpiryConfig{Deadline: time.Hour, Retention: config.ResultRetryWindow}).
+		WithClock(func() time.Time { return now })
+	_, err = sweeper.Sweep()
+	require.NoError(t, err)
+	order, err := database.GetOrder("sweep-rule-usage")
+	require.NoError(t, err)
+	assert.Nil(t, order)
+	usages, err := database.ListCharacterUsage("sweep-rules", sessionID, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, usages, 1)
+
+	// 삭제된 주문의 UUID는 다시 사용할 수 없음
+	err = database.StoreOrder(&database.UUIDMapping{UUID: "sweep-rule-usage", SessionID: sessionID})
+	assert.ErrorIs(t, err, database.ErrOrderExists)
+
+	// 규칙 기간이 지나면 사용량도 삭제
+	now = now.Add(8 * 24 * time.Hour)
+	_, err = sweeper.Sweep()
+	require.NoError(t, err)
+	usages, err = database.ListCharacterUsage("sweep-rules", sessionID, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
+
+func TestReserveRuleUsageKeepsOtherOrder(t *testing.T) {
+	require.NoError(t, database.InitDB())
+	defer database.CloseDB()
+
+	// 같은 UUID로 동시에 들어온 두 요청 중 먼저 기록한 요청의 사용량
+	require.NoError(t, ReserveRuleUsage("usage-race", "usage-race-a", "usage-race", disassembleMoney(10)))
+
+	// 나중 요청은 중복으로 거부되고 기존 사용량을 덮어쓰지 않음
+	err := ReserveRuleUsage("usage-race", "usage-race-b", "usage-race", disassembleMoney(500))
+	assert.ErrorIs(t, err, database.ErrOrderExists)
+	usages, err := database.ListCharacterUsage("usage-race", "usage-race-a", time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, usages, 1)
+	usages, err = database.ListCharacterUsage("usage-race", "usage-race-b", time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
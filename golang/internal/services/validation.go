@@ -1,54 +1,28 @@
 package services
 
 import (
+	"context"
+	"time"
+
 	"sample-game-backend/internal/database"
+	"sample-game-backend/internal/metrics"
 	"sample-game-backend/internal/models"
+	"sample-game-backend/internal/tracing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
 	keystoreService = NewKeystoreService()
 )
 
-// ValidateIntent intent validation
-func ValidateIntent(intent models.ExchangeIntent) bool {
-	// Validate allowed methods
-	allowedMethods := map[string]bool{
-		"mint":                 true,
-		"transfer":             true,
-		"burn":                 true,
-		"burn-permit":          true,
-		"transfer-from":        true,
-		"transfer-from-permit": true,
-	}
-
-	if !allowedMethods[intent.Method] {
-		return false
-	}
-
-	// Special validation for mint method
-	if intent.Method == "mint" {
-		// Must have at least one from item
-		if len(intent.From) == 0 {
-			return false
-		}
-
-		// All from items must be asset type
-		for _, from := range intent.From {
-			if from.Type != "asset" {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
 // GenerateValidatorSignature generate validator signature (sample implementation)
-func GenerateValidatorSignature(userSig hexutil.Bytes, digest common.Hash) (hexutil.Bytes, error) {
-	signature, err := keystoreService.Sign(digest.Bytes())
+func GenerateValidatorSignature(ctx context.Context, userSig hexutil.Bytes, digest common.Hash) (hexutil.Bytes, error) {
+	start := time.Now()
+	signature, err := keystoreService.Sign(ctx, digest.Bytes())
+	metrics.ObserveSigner(start, err)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +31,10 @@ func GenerateValidatorSignature(userSig hexutil.Bytes, digest common.Hash) (hexu
 }
 
 // ValidateAndProcessMint mint validation and processing
-func ValidateAndProcessMint(sessionID string, fromAssets []models.PairAsset) error {
+func ValidateAndProcessMint(ctx context.Context, sessionID, uuid string, fromAssets []models.PairAsset) error {
 	// Asset balance validation and deduction
-	return database.CheckAndDeductAssets(sessionID, fromAssets)
+	_, span := tracing.Start(ctx, "CheckAndDeductAssets", attribute.String("session_id", sessionID), attribute.Int("assets", len(fromAssets)))
+	err := database.CheckAndDeductAssets(sessionID, fromAssets, database.LedgerRef{Source: database.LedgerSourceValidate, Reference: uuid})
+	tracing.End(span, err)
+	return err
 }
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sample-game-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInitTracing(t *testing.T) {
+	for _, exporter := range []string{"", ExporterNone, ExporterStdout} {
+		shutdown, err := InitTracing(context.Background(), config.TracingConfig{Exporter: exporter, ServiceName: "tracing-test"})
+		require.NoError(t, err, exporter)
+		assert.NoError(t, shutdown(context.Background()))
+	}
+
+	// 지원하지 않는 exporter는 오류
+	_, err := InitTracing(context.Background(), config.TracingConfig{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
+
+func TestStartContinuesRemoteTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	// 램프 백엔드가 보낸 traceparent를 이어받음
+	carrier := propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+	_, ok := Start(ctx, "ok")
+	End(ok, nil)
+	_, failed := Start(ctx, "failed")
+	End(failed, errors.New("signer unavailable"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "signer unavailable", spans[1].Status().Description)
+}
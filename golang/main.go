@@ -1,41 +1,273 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"sample-game-backend/internal/catalog"
+	"sample-game-backend/internal/certs"
 	"sample-game-backend/internal/config"
 	"sample-game-backend/internal/database"
 	"sample-game-backend/internal/handlers"
+	"sample-game-backend/internal/metrics"
 	"sample-game-backend/internal/middleware"
+	"sample-game-backend/internal/orderquery"
+	"sample-game-backend/internal/provider"
+	"sample-game-backend/internal/services"
+	"sample-game-backend/internal/tracing"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
+	if err := run(); err != nil {
+		slog.Error("Server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run start the server and block until it is shut down (an error when it cannot start or serve)
+func run() error {
 	// Initialize configuration
 	cfg := config.InitConfig()
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Cancelled on SIGINT or SIGTERM to stop background jobs and drain the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Initialize tracing (spans are exported only when OTEL_TRACES_EXPORTER is set)
+	shutdownTracing, err := tracing.InitTracing(context.Background(), cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("initialize tracing: %w", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	// Initialize database
-	err := database.InitDB()
+	err = database.InitDB()
 	if err != nil {
-		slog.Error("Failed to initialize database", "error", err)
-		panic(err)
+		return fmt.Errorf("initialize database: %w", err)
 	}
 	defer database.CloseDB()
 
+	// Asset provider loads player profiles and balances for unknown sessions.
+	// Replace the demo provider (random balances) with your game's implementation.
+	database.SetAssetProvider(provider.NewDemoProvider())
+
+	// Bound stored sessions: admit only known sessions (optional), cap them and evict idle ones
+	database.SetSessionPolicy(database.SessionPolicy{
+		RequireKnown: cfg.Session.RequireKnown,
+		MaxSessions:  int(min(cfg.Session.MaxSessions, math.MaxInt32)),
+		IdleTTL:      cfg.Session.IdleTTL,
+	})
+
+	// Load asset catalog (embedded default catalog is used when no path is configured)
+	if cfg.Catalog.Path != "" {
+		if err := catalog.InitCatalog(cfg.Catalog.Path); err != nil {
+			return fmt.Errorf("load asset catalog: %w", err)
+		}
+	}
+
+	// Configure wallet enrollment
+	if err := services.InitEnrollment(cfg.Enrollment); err != nil {
+		return fmt.Errorf("initialize wallet enrollment: %w", err)
+	}
+
+	// Configure pending credits of disassemble orders
+	services.InitReservations(cfg.Reservation)
+
+	// Verify result receipts against the stored orders and, when an RPC endpoint is set, the chain
+	if err := services.InitReceiptVerification(cfg.Receipt); err != nil {
+		return fmt.Errorf("initialize receipt verification: %w", err)
+	}
+
+	// Append every issued validator signature to the hash-chained audit log
+	if err := services.InitAudit(cfg.Audit); err != nil {
+		return fmt.Errorf("open signature audit log: %w", err)
+	}
+	defer services.CloseAudit()
+
+	// Verify X-HMAC-SIGNATURE of CROSS RAMP requests
+	if err := middleware.InitHMAC(cfg.HMAC); err != nil {
+		return fmt.Errorf("initialize HMAC verification: %w", err)
+	}
+
+	// Require the CROSS RAMP client certificate on validate and result when a client CA is configured
+	middleware.InitClientCert(cfg.TLS)
+
+	// Throttle the public API by client IP, session and project
+	middleware.InitRateLimit(cfg.RateLimit)
+
+	// Authenticate support staff on the admin API
+	if err := middleware.InitAdminAuth(cfg.Admin); err != nil {
+		return fmt.Errorf("initialize admin API keys: %w", err)
+	}
+
+	// Load business rules
+	if cfg.Rules.Path != "" {
+		if err := services.InitRules(cfg.Rules.Path); err != nil {
+			return fmt.Errorf("load business rules: %w", err)
+		}
+	}
+
+	// Load conversion table
+	if cfg.Conversion.Path != "" {
+		if err := services.InitConversions(cfg.Conversion.Path); err != nil {
+			return fmt.Errorf("load conversion table: %w", err)
+		}
+	} else {
+		slog.Warn("No conversion table configured, intent exchange rates are not validated", "env", "CONVERSION_RULES_PATH")
+	}
+
+	// Background jobs stop with ctx and are waited for before the deferred closes run
+	var background sync.WaitGroup
+	defer func() {
+		stop()
+		background.Wait()
+	}()
+
+	// Expire orders that never receive a result webhook
+	sweeper := services.NewOrderSweeper(cfg.OrderExpiry)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		sweeper.Run(ctx)
+	}()
+
+	// Reconcile orders whose result webhook was missed against the Order Information Query API
+	if cfg.Reconcile.Network != "" {
+		reconciler := services.NewReconciler(orderquery.NewClient(cfg.Reconcile.APIURL, nil), cfg.Reconcile)
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			reconciler.Run(ctx)
+		}()
+	}
+
 	r := gin.Default()
 
+	// Take the client IP from X-Forwarded-For only when the connection comes from a trusted proxy
+	if err := r.SetTrustedProxies(cfg.TrustedProxyList()); err != nil {
+		return fmt.Errorf("set trusted proxies: %w", err)
+	}
+
+	// Start a server span per request, continuing the ramp backend's W3C trace context
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+
+	// Assign X-Request-ID and the request-scoped logger
+	r.Use(middleware.RequestIDMiddleware())
+
 	// Add CORS middleware
 	r.Use(middleware.CORSMiddleware())
 
+	// Record request metrics, including requests rejected by route middleware
+	r.Use(metrics.Middleware())
+	metrics.RegisterPendingOrders(database.CountPendingOrders, database.CountPendingReservations)
+
 	// Setup routes
 	handlers.SetupRoutes(r)
 
-	println("Server started on port 8080")
-	println("API endpoint: http://localhost:8080/api/assets?language=ko")
-	println("User action validation API: http://localhost:8080/api/validate")
-	println("Health check: http://localhost:8080/health")
-	println("Session-specific asset information is stored in go-memdb")
+	// Dependencies reported by /ready
+	handlers.RegisterReadinessCheck("signer", services.CheckSigner)
+	handlers.RegisterReadinessCheck("storage", func(ctx context.Context) error {
+		if err := database.Ping(); err != nil {
+			return err
+		}
+		return services.CheckAudit(ctx)
+	})
+	handlers.RegisterReadinessCheck("config", func(context.Context) error {
+		return cfg.Validate()
+	})
+
+	srv := &http.Server{
+		Addr:              cfg.Port,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Serve HTTPS when a certificate is configured, reloading the files when they change
+	scheme := "http"
+	if cfg.TLS.CertFile != "" {
+		reloader, err := certs.NewReloader(cfg.TLS)
+		if err != nil {
+			return fmt.Errorf("load TLS certificate: %w", err)
+		}
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			reloader.Run(ctx)
+		}()
+		srv.TLSConfig = reloader.TLSConfig()
+		handlers.RegisterReadinessCheck("tls", func(context.Context) error {
+			return reloader.Check(time.Now())
+		})
+		scheme = "https"
+	}
+
+	baseURL := scheme + "://" + localAddr(cfg.Port)
+	slog.Info("Server started",
+		"addr", cfg.Port,
+		"assets", baseURL+"/api/assets?language=ko",
+		"validate", baseURL+"/api/validate",
+		"health", baseURL+"/health",
+		"ready", baseURL+"/ready",
+	)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			// Certificates come from TLSConfig
+			serveErr <- srv.ListenAndServeTLS("", "")
+			return
+		}
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("listen on %s: %w", cfg.Port, err)
+	case <-ctx.Done():
+	}
+
+	// Report draining on /ready and keep serving while load balancers stop routing to this instance
+	slog.Info("Shutting down", "preStopDelay", cfg.PreStopDelay, "timeout", cfg.ShutdownTimeout)
+	handlers.SetDraining()
+	time.Sleep(cfg.PreStopDelay)
 
-	r.Run(cfg.Port)
+	// Stop accepting connections and let in-flight validate and result requests finish.
+	// Background jobs are then waited for and the deferred closes flush the audit log and tracing exporter.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Graceful shutdown did not complete", "error", err)
+	}
+	return nil
+}
+
+// localAddr address to reach the listen address from this host (localhost when no host is set)
+func localAddr(listen string) string {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return listen
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
 }
@@ -158,12 +158,14 @@ func TestValidateResultWorkflow(t *testing.T) {
 
 	fmt.Printf("✅ Validate API 성공: UUID=%s, SessionID=%s\n", testUUID, testSessionID)
 
-	// 2단계: UUID 매핑 확인
-	retrievedSessionID, err := database.GetSessionIDByUUID(testUUID)
-	assert.NoError(t, err, "Should be able to retrieve session ID by UUID")
-	assert.Equal(t, testSessionID, retrievedSessionID, "Retrieved session ID should match")
+	// 2단계: 주문 확인
+	order, err := database.GetOrder(testUUID)
+	assert.NoError(t, err, "Should be able to retrieve order by UUID")
+	require.NotNil(t, order, "Validated order should be stored")
+	assert.Equal(t, testSessionID, order.SessionID, "Retrieved session ID should match")
+	assert.Equal(t, database.OrderStatusValidated, order.Status, "Order should be validated")
 
-	fmt.Printf("✅ UUID 매핑 확인: UUID=%s -> SessionID=%s\n", testUUID, retrievedSessionID)
+	fmt.Printf("✅ 주문 확인: UUID=%s -> SessionID=%s\n", testUUID, order.SessionID)
 
 	// 3단계: Result API 호출
 	resultReq := SimpleResultRequest{
@@ -378,7 +380,9 @@ func TestValidateResultWorkflowWithInvalidUUID(t *testing.T) {
 	err = json.Unmarshal(resultRecorder.Body.Bytes(), &resultResp)
 	require.NoError(t, err, "Failed to unmarshal result response")
 
-	assert.Equal(t, "Invalid UUID or session not found", resultResp["error"], "Error message should indicate invalid UUID")
+	assert.Equal(t, false, resultResp["success"], "Result should not succeed")
+	assert.Equal(t, "INVALID_UUID", resultResp["errorCode"], "Error code should indicate invalid UUID")
+	assert.Equal(t, "Invalid UUID or session not found", resultResp["message"], "Error message should indicate invalid UUID")
 
 	fmt.Printf("✅ 잘못된 UUID 시나리오 테스트 성공: UUID=%s\n", invalidUUID)
 }